@@ -30,6 +30,10 @@ func newProvider(provider *msgv1.SmsProvider) ISms {
 	switch vendor {
 	case enumsv1.MessageSenderVendor_MESSAGE_SENDER_VENDOR_ALIYUN:
 		return NewAli(provider)
+	case enumsv1.MessageSenderVendor_MESSAGE_SENDER_VENDOR_VOLCENGINE:
+		return NewVolc(provider)
+	case enumsv1.MessageSenderVendor_MESSAGE_SENDER_VENDOR_TENCENT:
+		return NewTencent(provider)
 	}
 	panic("unsupported vendor type: " + provider.Vendor.String())
 }
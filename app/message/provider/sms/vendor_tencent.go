@@ -0,0 +1,220 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/byteflowing/base/pkg/sdk/tencent/sms"
+	"github.com/byteflowing/base/pkg/utils"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	msgv1 "github.com/byteflowing/proto/gen/go/msg/v1"
+	typesv1 "github.com/byteflowing/proto/gen/go/types/v1"
+)
+
+const (
+	tencentDefaultCountryCode = "86"
+	tencentDateFormat         = "20060102"
+	tencentReceiveTimeFormat  = "2006-01-02 15:04:05"
+	tencentMaxPageSize        = 100
+)
+
+// 腾讯云返回的时间为北京时间
+var tencentLocation = time.FixedZone("CST", 8*3600)
+
+// Tencent 腾讯云短信，请求中的account为SmsSdkAppId
+// 模板变量按位置替换，模板参数的key使用1、2、3...对应模板中的{1}{2}{3}
+type Tencent struct {
+	cli *sms.Sms
+}
+
+func NewTencent(c *msgv1.SmsProvider) *Tencent {
+	cfg := &sms.Config{
+		SecretID:  c.AccessKey,
+		SecretKey: c.SecretKey,
+	}
+	if c.SecurityToken != nil {
+		cfg.Token = *c.SecurityToken
+	}
+	cli, err := sms.New(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return &Tencent{
+		cli: cli,
+	}
+}
+
+func (t *Tencent) SendSingleSms(ctx context.Context, req *msgv1.SendSmsReq) (err error) {
+	res, err := t.cli.SendSms(ctx, &sms.SendSmsRequest{
+		PhoneNumberSet:   []string{tencentPhoneNumber(req.PhoneNumber)},
+		SmsSdkAppId:      req.Account,
+		SignName:         req.SignName,
+		TemplateId:       req.TemplateCode,
+		TemplateParamSet: tencentTemplateParams(req.TemplateParams),
+	})
+	if err != nil {
+		return err
+	}
+	if len(res.SendStatusSet) == 0 {
+		return fmt.Errorf("[requestID:%s] send status is empty", res.RequestId)
+	}
+	if st := res.SendStatusSet[0]; st.Code != sms.StatusOk {
+		return fmt.Errorf("[requestID:%s, code:%s] errMsg:%s", res.RequestId, st.Code, st.Message)
+	}
+	return nil
+}
+
+// QuerySmsStatistics 按天查询回执统计，腾讯云不支持按签名和模板类型过滤
+func (t *Tencent) QuerySmsStatistics(ctx context.Context, req *msgv1.QuerySmsStatisticsReq) (*msgv1.QuerySmsStatisticsResp, error) {
+	if req.StartDate == nil || req.EndDate == nil {
+		return nil, errors.New("start date or end date is nil")
+	}
+	if req.Page <= 0 || req.Size <= 0 {
+		return nil, errors.New("page or size is invalid")
+	}
+	start := truncateDay(req.StartDate.AsTime())
+	end := truncateDay(req.EndDate.AsTime())
+	if end.Before(start) {
+		return nil, errors.New("end date is before start date")
+	}
+	total := int64(end.Sub(start)/(24*time.Hour)) + 1
+	statistics := make([]*msgv1.SmsSendStatistic, 0, req.Size)
+	for i := (int64(req.Page) - 1) * int64(req.Size); i < total && len(statistics) < int(req.Size); i++ {
+		day := start.AddDate(0, 0, int(i))
+		date := day.Format(tencentDateFormat)
+		res, err := t.cli.CallbackStatusStatistics(ctx, &sms.StatisticsRequest{
+			BeginTime:   date + "00",
+			EndTime:     date + "23",
+			SmsSdkAppId: req.Account,
+		})
+		if err != nil {
+			return nil, err
+		}
+		statistic := &msgv1.SmsSendStatistic{Date: timestamppb.New(day)}
+		if s := res.CallbackStatusStatistics; s != nil {
+			statistic.Total = s.RequestSuccessCount
+			statistic.Success = s.CallbackSuccessCount
+			statistic.Failed = s.CallbackFailCount
+			statistic.NoResponse = max(s.RequestSuccessCount-s.CallbackCount, 0)
+		}
+		statistics = append(statistics, statistic)
+	}
+	return &msgv1.QuerySmsStatisticsResp{
+		Page:       req.Page,
+		Size:       req.Size,
+		Total:      total,
+		TotalPages: utils.CalcTotalPage(total, req.Size),
+		Vendor:     req.Vendor,
+		Account:    req.Account,
+		Statistics: statistics,
+	}, nil
+}
+
+// QuerySmsSendDetail 查询号码当天的回执，腾讯云不返回总数和短信内容，Total为已查询到的数量
+func (t *Tencent) QuerySmsSendDetail(ctx context.Context, req *msgv1.QuerySmsSendDetailReq) (*msgv1.QuerySmsSendDetailResp, error) {
+	if req.PhoneNumber == nil || req.SendDate == nil {
+		return nil, errors.New("phone number or send date is nil")
+	}
+	if req.Page <= 0 || req.Size <= 0 || req.Size > tencentMaxPageSize {
+		return nil, errors.New("page or size is invalid")
+	}
+	begin := truncateDay(req.SendDate.AsTime())
+	offset := (int64(req.Page) - 1) * int64(req.Size)
+	res, err := t.cli.PullSmsSendStatusByPhoneNumber(ctx, &sms.PullSmsSendStatusByPhoneNumberRequest{
+		BeginTime:   begin.Unix(),
+		EndTime:     begin.Add(24*time.Hour - time.Second).Unix(),
+		Offset:      offset,
+		Limit:       int64(req.Size),
+		PhoneNumber: tencentPhoneNumber(req.PhoneNumber),
+		SmsSdkAppId: req.Account,
+	})
+	if err != nil {
+		return nil, err
+	}
+	details := make([]*msgv1.SmsSendDetail, 0, len(res.PullSmsSendStatusSet))
+	for _, d := range res.PullSmsSendStatusSet {
+		detail := &msgv1.SmsSendDetail{
+			PhoneNumber: d.SubscriberNumber,
+			Status:      tencentReportStatusToStatus(d.ReportStatus),
+			OutId:       d.SessionContext,
+			ErrCode:     d.Description,
+		}
+		if d.UserReceiveTime != "" {
+			receiveDate, err := time.ParseInLocation(tencentReceiveTimeFormat, d.UserReceiveTime, tencentLocation)
+			if err != nil {
+				return nil, err
+			}
+			detail.ReceiveDate = timestamppb.New(receiveDate)
+		}
+		details = append(details, detail)
+	}
+	total := offset + int64(len(details))
+	return &msgv1.QuerySmsSendDetailResp{
+		Page:       req.Page,
+		Size:       req.Size,
+		Total:      total,
+		TotalPages: utils.CalcTotalPage(total, req.Size),
+		Vendor:     req.Vendor,
+		Account:    req.Account,
+		Details:    details,
+	}, nil
+}
+
+// tencentPhoneNumber 腾讯云要求E.164格式，没有区号时默认为中国大陆
+func tencentPhoneNumber(phone *typesv1.PhoneNumber) string {
+	number := phone.GetNumber()
+	if strings.HasPrefix(number, "+") {
+		return number
+	}
+	countryCode := strings.TrimPrefix(phone.GetCountryCode(), "+")
+	if countryCode == "" {
+		countryCode = tencentDefaultCountryCode
+	}
+	return "+" + countryCode + number
+}
+
+// tencentTemplateParams key为数字时按数字排序，否则按字典序排序
+func tencentTemplateParams(params map[string]string) []string {
+	if len(params) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, errA := strconv.Atoi(keys[i])
+		b, errB := strconv.Atoi(keys[j])
+		if errA == nil && errB == nil {
+			return a < b
+		}
+		return keys[i] < keys[j]
+	})
+	values := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, params[k])
+	}
+	return values
+}
+
+func tencentReportStatusToStatus(st string) enumsv1.SmsSendStatus {
+	switch st {
+	case sms.ReportStatusSuccess:
+		return enumsv1.SmsSendStatus_SMS_SEND_STATUS_SUCCESS
+	case sms.ReportStatusFail:
+		return enumsv1.SmsSendStatus_SMS_SEND_STATUS_FAILED
+	}
+	return enumsv1.SmsSendStatus_SMS_SEND_STATUS_UNSPECIFIED
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.In(tencentLocation)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, tencentLocation)
+}
@@ -0,0 +1,33 @@
+package sms
+
+import (
+	"context"
+
+	"github.com/byteflowing/base/ecode"
+	"github.com/byteflowing/base/pkg/sdk/bytedance/volc/sms"
+	msgv1 "github.com/byteflowing/proto/gen/go/msg/v1"
+)
+
+type Volc struct {
+	cli *sms.Sms
+}
+
+func NewVolc(c *msgv1.SmsProvider) *Volc {
+	return &Volc{
+		cli: sms.NewSms(c),
+	}
+}
+
+func (v *Volc) SendSingleSms(_ context.Context, req *msgv1.SendSmsReq) (err error) {
+	_, err = v.cli.SendSms(req)
+	return err
+}
+
+// QuerySmsStatistics 火山引擎没有提供发送统计的OpenAPI
+func (v *Volc) QuerySmsStatistics(ctx context.Context, req *msgv1.QuerySmsStatisticsReq) (*msgv1.QuerySmsStatisticsResp, error) {
+	return nil, ecode.ErrMsgInterfaceUnsupported
+}
+
+func (v *Volc) QuerySmsSendDetail(ctx context.Context, req *msgv1.QuerySmsSendDetailReq) (*msgv1.QuerySmsSendDetailResp, error) {
+	return v.cli.QuerySendDetail(req)
+}
@@ -237,6 +237,10 @@ func (m *MessageService) VerifyCaptcha(ctx context.Context, req *msgv1.VerifyCap
 	if err != nil {
 		return nil, err
 	}
+	// 和SendCaptcha一致，Result为空表示验证通过
+	if res.Code == captcha.ValueVerifySuccess {
+		return &msgv1.VerifyCaptchaResp{}, nil
+	}
 	return &msgv1.VerifyCaptchaResp{Result: &msgv1.VerifyCaptchaResp_VerifyResult{
 		Fails: uint32(res.Fails),
 		Quota: q,
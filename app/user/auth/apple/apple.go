@@ -0,0 +1,261 @@
+package apple
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/byteflowing/base/app/user/common"
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/ecode"
+	"github.com/byteflowing/base/pkg/oidc"
+	applev1 "github.com/byteflowing/proto/gen/go/apple/v1"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	issuer = "https://appleid.apple.com"
+	// 用户选择隐藏邮箱时Apple提供的中转邮箱域名
+	privateRelayDomain = "@privaterelay.appleid.com"
+)
+
+type AccountManager struct {
+	clientID  string
+	idService *common.IDService
+	verifier  *oidc.Client
+}
+
+// NewAccountManager Apple登录
+// config.ClientIds 第一个作为user_auth的appid，iOS的bundle id和web的services id都可以作为aud
+func NewAccountManager(idService *common.IDService, config *applev1.AccountConfig) *AccountManager {
+	if len(config.ClientIds) == 0 {
+		panic("apple sign in requires at least one client id")
+	}
+	return &AccountManager{
+		clientID:  config.ClientIds[0],
+		idService: idService,
+		verifier: oidc.New(&oidc.Config{
+			Issuer:    issuer,
+			ClientID:  config.ClientIds[0],
+			Audiences: config.ClientIds[1:],
+		}),
+	}
+}
+
+func (am *AccountManager) Close() error {
+	return am.verifier.Close()
+}
+
+func (am *AccountManager) Authenticate(ctx context.Context, req *userv1.SignInReq, tx *query.Query) (*userv1.SignInResult, error) {
+	if req == nil || req.SignInType != enumsv1.SignInType_SIGN_IN_TYPE_APPLE {
+		return nil, errors.New("invalid params")
+	}
+	param := req.GetApple()
+	identity, err := am.verify(ctx, param)
+	if err != nil {
+		return nil, err
+	}
+	user, needAuth, err := am.getUser(ctx, identity, req.GetTenantId(), tx)
+	if err != nil {
+		return nil, err
+	}
+	created := false
+	if user == nil {
+		number, err := am.idService.GetShortID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		id, err := am.idService.GetGlobalID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		agent := req.GetAgent()
+		if agent == nil {
+			agent = &userv1.Agent{}
+		}
+		user = &model.UserAccount{
+			ID:               id,
+			TenantID:         req.GetTenantId(),
+			Number:           number,
+			Phone:            common.PlaceholderPhone(id),
+			Email:            common.PlaceholderEmail(id),
+			Status:           int16(enumsv1.UserStatus_USER_STATUS_OK),
+			Source:           int16(enumsv1.UserSource_USER_SOURCE_APP_IOS),
+			SignupType:       int16(enumsv1.SignUpType_SIGN_UP_TYPE_APPLE),
+			RegisterIP:       agent.Ip,
+			RegisterDevice:   agent.Device,
+			RegisterAgent:    agent.Agent,
+			RegisterLocation: common.LocationToString(agent.Location),
+		}
+		// Apple只在用户首次授权时把姓名返回给客户端
+		if param.Name != "" {
+			user.Name = &param.Name
+		}
+		if identity.email != "" && identity.emailVerified {
+			taken, err := am.emailTaken(ctx, tx, req.GetTenantId(), identity.email)
+			if err != nil {
+				return nil, err
+			}
+			if !taken {
+				user.Email = identity.email
+				user.EmailVerified = true
+			}
+		}
+		if err := tx.UserAccount.WithContext(ctx).Create(user); err != nil {
+			return nil, err
+		}
+		created = true
+	} else {
+		if !common.IsUserValid(user.Status) {
+			return nil, ecode.ErrUserDisabled
+		}
+	}
+	if needAuth {
+		if err := am.createAuth(ctx, tx, req.GetTenantId(), user.ID, identity.subject); err != nil {
+			return nil, err
+		}
+	}
+	return &userv1.SignInResult{
+		User:       common.UserModelToUser(user),
+		Identifier: identity.subject,
+		Created:    created,
+	}, nil
+}
+
+// Link 已登录的用户（e.g. 手机号账号）绑定Apple账号
+func (am *AccountManager) Link(ctx context.Context, req *userv1.SignInReq, tx *query.Query, user *model.UserAccount) error {
+	if req == nil || req.SignInType != enumsv1.SignInType_SIGN_IN_TYPE_APPLE {
+		return errors.New("invalid params")
+	}
+	identity, err := am.verify(ctx, req.GetApple())
+	if err != nil {
+		return err
+	}
+	_, err = am.findAuth(ctx, tx, user.TenantID, identity.subject)
+	if err == nil {
+		return ecode.ErrUserAuthAlreadyLinked
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return am.createAuth(ctx, tx, user.TenantID, user.ID, identity.subject)
+}
+
+type identity struct {
+	subject       string
+	email         string
+	emailVerified bool
+	privateEmail  bool
+}
+
+// verify 校验identity token的签名、iss、aud、exp以及nonce
+// 客户端向Apple发起授权时传的是原始nonce的sha256，token中的nonce是摘要值
+// nonce是必传的，否则截获的identity token可以被重放
+// 文档：https://developer.apple.com/documentation/sign_in_with_apple/sign_in_with_apple_rest_api/verifying_a_user
+func (am *AccountManager) verify(ctx context.Context, param *applev1.AppleSignInReq) (*identity, error) {
+	if param == nil || param.IdentityToken == "" || param.Nonce == "" {
+		return nil, ecode.ErrParams
+	}
+	claims, err := am.verifier.VerifyIDToken(ctx, param.IdentityToken, "")
+	if err != nil {
+		if errors.Is(err, oidc.ErrInvalidToken) {
+			return nil, ecode.ErrUserAuthInvalid
+		}
+		return nil, err
+	}
+	sum := sha256.Sum256([]byte(param.Nonce))
+	if n, _ := claims["nonce"].(string); n != hex.EncodeToString(sum[:]) {
+		return nil, ecode.ErrUserAuthInvalid
+	}
+	sub, _ := claims.GetSubject()
+	if sub == "" {
+		return nil, ecode.ErrUserAuthInvalid
+	}
+	email, _ := claims["email"].(string)
+	email = common.NormalizeEmail(email)
+	return &identity{
+		subject:       sub,
+		email:         email,
+		emailVerified: claimBool(claims, "email_verified"),
+		privateEmail:  claimBool(claims, "is_private_email") || strings.HasSuffix(email, privateRelayDomain),
+	}, nil
+}
+
+// getUser 优先使用已绑定的身份，未绑定时使用Apple验证过的真实邮箱关联租户下已验证邮箱的账号
+// 中转邮箱只对本应用有效，不用于关联已有账号
+func (am *AccountManager) getUser(ctx context.Context, identity *identity, tenantID string, tx *query.Query) (m *model.UserAccount, needCreateAuth bool, err error) {
+	accountQ := tx.UserAccount
+	userAuth, err := am.findAuth(ctx, tx, tenantID, identity.subject)
+	if err == nil {
+		if userAuth.Status != int16(enumsv1.AuthStatus_AUTH_STATUS_OK) {
+			return nil, false, ecode.ErrUserAuthInvalid
+		}
+		m, err = accountQ.WithContext(ctx).Where(accountQ.TenantID.Eq(tenantID), accountQ.ID.Eq(userAuth.UID)).Take()
+		return m, false, err
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+	if identity.email == "" || !identity.emailVerified || identity.privateEmail {
+		return nil, true, nil
+	}
+	userAccount, err := accountQ.WithContext(ctx).Where(
+		accountQ.TenantID.Eq(tenantID),
+		accountQ.Email.Eq(identity.email),
+		accountQ.EmailVerified.Is(true),
+	).Take()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+	return userAccount, true, nil
+}
+
+// findAuth 同一个Apple应用可能被多个租户使用，按租户、类型和appid查找已绑定的身份
+func (am *AccountManager) findAuth(ctx context.Context, tx *query.Query, tenantID, subject string) (*model.UserAuth, error) {
+	q := tx.UserAuth
+	return q.WithContext(ctx).Where(
+		q.TenantID.Eq(tenantID),
+		q.Type.Eq(int16(enumsv1.SignInType_SIGN_IN_TYPE_APPLE)),
+		q.Appid.Eq(am.clientID),
+		q.OpenID.Eq(subject),
+	).Take()
+}
+
+func (am *AccountManager) createAuth(ctx context.Context, tx *query.Query, tenantID string, uid int64, subject string) error {
+	return tx.UserAuth.WithContext(ctx).Create(&model.UserAuth{
+		TenantID: tenantID,
+		UID:      uid,
+		Type:     int16(enumsv1.SignInType_SIGN_IN_TYPE_APPLE),
+		Status:   int16(enumsv1.AuthStatus_AUTH_STATUS_OK),
+		Appid:    am.clientID,
+		OpenID:   subject,
+	})
+}
+
+func (am *AccountManager) emailTaken(ctx context.Context, tx *query.Query, tenantID, email string) (bool, error) {
+	accountQ := tx.UserAccount
+	count, err := accountQ.WithContext(ctx).Where(accountQ.TenantID.Eq(tenantID), accountQ.Email.Eq(email)).Count()
+	return count > 0, err
+}
+
+// claimBool Apple的email_verified和is_private_email可能是字符串"true"
+func claimBool(claims jwt.MapClaims, key string) bool {
+	switch v := claims[key].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
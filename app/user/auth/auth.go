@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 
+	"github.com/byteflowing/base/app/user/dal/model"
 	"github.com/byteflowing/base/app/user/dal/query"
 	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
 )
@@ -10,3 +11,20 @@ import (
 type Auth interface {
 	Authenticate(ctx context.Context, req *userv1.SignInReq, tx *query.Query) (*userv1.SignInResult, error)
 }
+
+// Revoker 第三方登录方式可以实现Revoker，在用户退出登录或解绑时撤销第三方的用户凭证
+// identifier为登录时SignInResult中的Identifier
+type Revoker interface {
+	Revoke(ctx context.Context, identifier string) error
+}
+
+// Linker 支持已登录用户绑定的第三方登录方式实现Linker，req中携带第三方的登录凭证
+type Linker interface {
+	Link(ctx context.Context, req *userv1.SignInReq, tx *query.Query, user *model.UserAccount) error
+}
+
+// Closer 持有http连接等资源的登录方式实现Closer，租户配置变化后旧的登录方式会被关闭
+// 关闭时可能仍有请求在使用，只能释放空闲的资源
+type Closer interface {
+	Close() error
+}
@@ -0,0 +1,119 @@
+package guest
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/byteflowing/base/app/user/common"
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/ecode"
+	"github.com/byteflowing/base/pkg/utils/crypto"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+type AccountManager struct {
+	idService *common.IDService
+}
+
+// NewAccountManager 游客登录，e.g. 小程序用户登录前浏览
+// 游客账号和设备绑定，同一租户下同一设备再次登录得到同一个游客账号
+func NewAccountManager(idService *common.IDService) *AccountManager {
+	return &AccountManager{idService: idService}
+}
+
+func (am *AccountManager) Authenticate(ctx context.Context, req *userv1.SignInReq, tx *query.Query) (*userv1.SignInResult, error) {
+	if req == nil || req.SignInType != enumsv1.SignInType_SIGN_IN_TYPE_GUEST {
+		return nil, errors.New("invalid params")
+	}
+	agent := req.GetAgent()
+	if agent.GetDevice() == "" {
+		return nil, ecode.ErrParams
+	}
+	identifier := deviceIdentifier(req.GetTenantId(), agent.GetDevice())
+	user, err := am.getUser(ctx, tx, identifier)
+	if err != nil {
+		return nil, err
+	}
+	created := false
+	if user == nil {
+		number, err := am.idService.GetShortID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		id, err := am.idService.GetGlobalID(ctx)
+		if err != nil {
+			return nil, err
+		}
+		user = &model.UserAccount{
+			ID:               id,
+			TenantID:         req.GetTenantId(),
+			Number:           number,
+			Phone:            common.PlaceholderPhone(id),
+			Email:            common.PlaceholderEmail(id),
+			Status:           int16(enumsv1.UserStatus_USER_STATUS_OK),
+			Source:           int16(enumsv1.UserSource_USER_SOURCE_GUEST),
+			SignupType:       int16(enumsv1.SignUpType_SIGN_UP_TYPE_GUEST),
+			RegisterIP:       agent.Ip,
+			RegisterDevice:   agent.Device,
+			RegisterAgent:    agent.Agent,
+			RegisterLocation: common.LocationToString(agent.Location),
+		}
+		if err := tx.UserAccount.WithContext(ctx).Create(user); err != nil {
+			return nil, err
+		}
+		if err := tx.UserAuth.WithContext(ctx).Create(&model.UserAuth{
+			TenantID: req.GetTenantId(),
+			UID:      id,
+			Type:     int16(enumsv1.SignInType_SIGN_IN_TYPE_GUEST),
+			Status:   int16(enumsv1.AuthStatus_AUTH_STATUS_OK),
+			OpenID:   identifier,
+		}); err != nil {
+			return nil, err
+		}
+		created = true
+	} else if !common.IsUserValid(user.Status) {
+		return nil, ecode.ErrUserDisabled
+	}
+	return &userv1.SignInResult{
+		User:       common.UserModelToUser(user),
+		Identifier: identifier,
+		Created:    created,
+	}, nil
+}
+
+// getUser 设备绑定的游客账号，升级后绑定会被删除，这里只会查到仍是游客的账号
+func (am *AccountManager) getUser(ctx context.Context, tx *query.Query, identifier string) (*model.UserAccount, error) {
+	q := tx.UserAuth
+	userAuth, err := q.WithContext(ctx).Where(
+		q.OpenID.Eq(identifier),
+		q.Type.Eq(int16(enumsv1.SignInType_SIGN_IN_TYPE_GUEST)),
+	).Take()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	accountQ := tx.UserAccount
+	user, err := accountQ.WithContext(ctx).Where(accountQ.ID.Eq(userAuth.UID)).Take()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if user.Source != int16(enumsv1.UserSource_USER_SOURCE_GUEST) {
+		return nil, ecode.ErrUserAuthInvalid
+	}
+	return user, nil
+}
+
+// deviceIdentifier 设备在租户下的标识，作为user_auth的open_id
+// open_id上有全局唯一索引，不同租户的同一设备需要区分
+func deviceIdentifier(tenantID, device string) string {
+	return crypto.Sha256Hex([]byte(tenantID + ":" + device))
+}
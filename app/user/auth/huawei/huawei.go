@@ -7,12 +7,15 @@ import (
 	"strings"
 	"time"
 
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
 	"gorm.io/gorm"
 
 	"github.com/byteflowing/base/app/user/common"
 	"github.com/byteflowing/base/app/user/dal/model"
 	"github.com/byteflowing/base/app/user/dal/query"
 	"github.com/byteflowing/base/ecode"
+	"github.com/byteflowing/base/pkg/logx"
 	"github.com/byteflowing/base/pkg/redis"
 	"github.com/byteflowing/base/pkg/sdk/huawei/account"
 	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
@@ -20,13 +23,23 @@ import (
 	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
 )
 
+const (
+	// 华为用户级refresh token的有效期为180天
+	userTokenTTL = 180 * 24 * time.Hour
+	// 获取头像和昵称时同时获取昵称
+	getNickName = 1
+)
+
 type AccountManager struct {
-	appTokenKey string
-	clientID    string
-	idService   *common.IDService
-	cli         *account.Account
-	rdb         *redis.Redis
-	lock        *redis.Lock
+	appTokenKey     string
+	userTokenPrefix string
+	clientID        string
+	maxRiskLevel    int32
+	riskAction      enumsv1.RiskAction
+	idService       *common.IDService
+	cli             *account.Account
+	rdb             *redis.Redis
+	lock            *redis.Lock
 }
 
 func NewAccountManager(
@@ -45,18 +58,33 @@ func NewAccountManager(
 			TTL:    30 * time.Second,
 			Wait:   10 * time.Millisecond,
 		}),
-		idService:   idService,
-		appTokenKey: keyPrefix + ":hw_app_token:" + config.ClientId,
-		clientID:    config.ClientId,
+		idService:       idService,
+		appTokenKey:     keyPrefix + ":hw_app_token:" + config.ClientId,
+		userTokenPrefix: keyPrefix + ":hw_user_token:" + config.ClientId + ":",
+		clientID:        config.ClientId,
+		maxRiskLevel:    config.MaxRiskLevel,
+		riskAction:      config.RiskAction,
 	}
 }
 
+func (am *AccountManager) Close() error {
+	return am.cli.Close()
+}
+
 func (am *AccountManager) Authenticate(ctx context.Context, req *userv1.SignInReq, tx *query.Query) (*userv1.SignInResult, error) {
 	if req == nil || req.SignInType != enumsv1.SignInType_SIGN_IN_TYPE_HUAWEI {
 		return nil, errors.New("invalid params")
 	}
 	param := req.GetHuawei()
-	result, err := am.cli.SignIn(ctx, param)
+	stepUp, err := am.checkRisk(ctx, param.RiskToken)
+	if err != nil {
+		return nil, err
+	}
+	// 风险token和授权码不属于一键登录接口的参数
+	quickLogin := proto.Clone(param).(*huaweiv1.HuaweiSignInReq)
+	quickLogin.RiskToken = ""
+	quickLogin.AuthorizationCode = ""
+	result, err := am.cli.SignIn(ctx, quickLogin)
 	if err != nil {
 		return nil, err
 	}
@@ -64,6 +92,14 @@ func (am *AccountManager) Authenticate(ctx context.Context, req *userv1.SignInRe
 	if err != nil {
 		return nil, err
 	}
+	var userToken string
+	if param.AuthorizationCode != "" {
+		userToken, err = am.getUserToken(ctx, param.AuthorizationCode, result.OpenId)
+		if err != nil {
+			return nil, err
+		}
+	}
+	created := false
 	if user == nil {
 		number, err := am.idService.GetShortID(ctx)
 		if err != nil {
@@ -92,9 +128,13 @@ func (am *AccountManager) Authenticate(ctx context.Context, req *userv1.SignInRe
 			RegisterAgent:    agent.Agent,
 			RegisterLocation: common.LocationToString(agent.Location),
 		}
+		if userToken != "" {
+			am.fillAvatarNickName(ctx, userToken, user)
+		}
 		if err := tx.UserAccount.WithContext(ctx).Create(user); err != nil {
 			return nil, err
 		}
+		created = true
 	} else {
 		if !common.IsUserValid(user.Status) {
 			return nil, ecode.ErrUserDisabled
@@ -117,6 +157,8 @@ func (am *AccountManager) Authenticate(ctx context.Context, req *userv1.SignInRe
 	return &userv1.SignInResult{
 		User:       common.UserModelToUser(user),
 		Identifier: result.OpenId,
+		Created:    created,
+		StepUp:     stepUp,
 	}, nil
 }
 
@@ -151,6 +193,86 @@ func (am *AccountManager) getUser(ctx context.Context, result *huaweiv1.HuaweiSi
 	return userAccount, true, nil
 }
 
+// Revoke 用户退出登录或者解绑华为账号时取消华为用户级凭证，identifier为openID
+func (am *AccountManager) Revoke(ctx context.Context, identifier string) error {
+	key := am.userTokenPrefix + identifier
+	token, err := am.rdb.Get(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil
+		}
+		return err
+	}
+	if _, err := am.cli.CancelUserToken(ctx, &huaweiv1.HuaweiCancelUserTokenReq{Token: token}); err != nil {
+		return err
+	}
+	return am.rdb.Del(ctx, key).Err()
+}
+
+// checkRisk 使用应用级token查询用户风险等级，超过配置的等级时拒绝登录或要求二次验证
+// 未配置风险等级或者客户端没有上报风险token时跳过
+func (am *AccountManager) checkRisk(ctx context.Context, riskToken string) (stepUp bool, err error) {
+	if am.maxRiskLevel <= 0 || riskToken == "" {
+		return false, nil
+	}
+	appToken, err := am.getAppToken(ctx)
+	if err != nil {
+		return false, err
+	}
+	resp, err := am.cli.GetUserRiskLevel(ctx, &huaweiv1.HuaweiGetUserRiskLevelReq{
+		AccessToken: appToken,
+		RiskToken:   riskToken,
+	})
+	if err != nil {
+		return false, err
+	}
+	if err := am.parseError(resp.Error, resp.SubError, resp.ErrorDescription); err != nil {
+		return false, err
+	}
+	if resp.RiskLevel <= am.maxRiskLevel {
+		return false, nil
+	}
+	if am.riskAction == enumsv1.RiskAction_RISK_ACTION_STEP_UP {
+		return true, nil
+	}
+	return false, ecode.ErrUserSignInRisky
+}
+
+// getUserToken 使用授权码换取用户级token，refresh token缓存下来用于退出登录时取消授权
+func (am *AccountManager) getUserToken(ctx context.Context, code, openID string) (string, error) {
+	resp, err := am.cli.GetUserToken(ctx, &huaweiv1.HuaweiGetUserTokenReq{Code: code})
+	if err != nil {
+		return "", err
+	}
+	if err := am.parseError(resp.Error, resp.SubError, resp.ErrorDescription); err != nil {
+		return "", err
+	}
+	if resp.RefreshToken != "" {
+		if err := am.rdb.Set(ctx, am.userTokenPrefix+openID, resp.RefreshToken, userTokenTTL).Err(); err != nil {
+			return "", err
+		}
+	}
+	return resp.AccessToken, nil
+}
+
+// fillAvatarNickName 新注册用户使用华为账号的头像和昵称，获取失败不影响登录
+func (am *AccountManager) fillAvatarNickName(ctx context.Context, userToken string, user *model.UserAccount) {
+	resp, err := am.cli.GetUserAvatarNickName(ctx, &huaweiv1.HuaweiGetUserAvatarNickNameReq{
+		AccessToken: userToken,
+		GetNickName: getNickName,
+	})
+	if err != nil {
+		logx.CtxWarn(ctx, "get huawei avatar and nickname failed", zap.Error(err))
+		return
+	}
+	if resp.DisplayName != "" {
+		user.Alias_ = &resp.DisplayName
+	}
+	if resp.HeadPictureUrl != "" {
+		user.Avatar = &resp.HeadPictureUrl
+	}
+}
+
 func (am *AccountManager) parseCountryCode(countryCode string) string {
 	return strings.TrimLeft(countryCode, "00")
 }
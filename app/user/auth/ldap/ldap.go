@@ -0,0 +1,264 @@
+package ldap
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/byteflowing/base/app/user/common"
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/ecode"
+	ldapsdk "github.com/byteflowing/base/pkg/ldap"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+// 默认属性，OpenLDAP（inetOrgPerson）和AD都支持
+const (
+	defaultEmailAttr = "mail"
+	defaultNameAttr  = "displayName"
+	defaultAliasAttr = "cn"
+)
+
+type attrMapping struct {
+	id    string // 为空时使用DN作为用户标识，AD建议配置objectGUID，改名或者移动OU后不变
+	email string
+	name  string
+	alias string
+}
+
+// Manager 企业目录账号密码登录，首次登录时自动创建账号
+type Manager struct {
+	url        string
+	trustEmail bool
+	mapping    *attrMapping
+	idService  *common.IDService
+	cli        *ldapsdk.Client
+}
+
+func NewManager(idService *common.IDService, config *userv1.LdapConfig) (*Manager, error) {
+	mapping := newAttrMapping(config.AttributeMapping)
+	attrs := []string{mapping.email, mapping.name, mapping.alias}
+	if mapping.id != "" {
+		attrs = append(attrs, mapping.id)
+	}
+	cli, err := ldapsdk.New(newClientConfig(config, attrs))
+	if err != nil {
+		return nil, err
+	}
+	return &Manager{
+		url:        config.Url,
+		trustEmail: config.TrustEmail,
+		mapping:    mapping,
+		idService:  idService,
+		cli:        cli,
+	}, nil
+}
+
+// ValidateConfig 校验配置，不创建客户端
+func ValidateConfig(config *userv1.LdapConfig) error {
+	return ldapsdk.Validate(newClientConfig(config, nil))
+}
+
+func newClientConfig(config *userv1.LdapConfig, attrs []string) *ldapsdk.Config {
+	return &ldapsdk.Config{
+		URL:                config.Url,
+		StartTLS:           config.StartTls,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+		CACert:             config.CaCert,
+		Timeout:            config.Timeout.AsDuration(),
+		BindDNTemplate:     config.BindDnTemplate,
+		BindDN:             config.BindDn,
+		BindPassword:       config.BindPassword,
+		BaseDN:             config.BaseDn,
+		UserFilter:         config.UserFilter,
+		Attributes:         attrs,
+	}
+}
+
+func newAttrMapping(m *userv1.LdapAttributeMapping) *attrMapping {
+	mapping := &attrMapping{
+		email: defaultEmailAttr,
+		name:  defaultNameAttr,
+		alias: defaultAliasAttr,
+	}
+	if m == nil {
+		return mapping
+	}
+	set := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	set(&mapping.id, m.Id)
+	set(&mapping.email, m.Email)
+	set(&mapping.name, m.Name)
+	set(&mapping.alias, m.Alias)
+	return mapping
+}
+
+func (m *Manager) Authenticate(ctx context.Context, req *userv1.SignInReq, tx *query.Query) (*userv1.SignInResult, error) {
+	if req == nil || req.SignInType != enumsv1.SignInType_SIGN_IN_TYPE_LDAP {
+		return nil, errors.New("invalid params")
+	}
+	param := req.GetLdap()
+	if param == nil || param.Username == "" || param.Password == "" {
+		return nil, ecode.ErrParams
+	}
+	entry, err := m.cli.Authenticate(ctx, param.Username, param.Password)
+	if err != nil {
+		if errors.Is(err, ldapsdk.ErrInvalidCredentials) {
+			return nil, ecode.ErrUserPasswordInvalid
+		}
+		return nil, err
+	}
+	subject := m.subject(entry)
+	if subject == "" {
+		return nil, ecode.ErrUserAuthInvalid
+	}
+	email := common.NormalizeEmail(entry.Get(m.mapping.email))
+	emailVerified := email != "" && m.trustEmail
+	user, needAuth, err := m.getUser(ctx, tx, req.GetTenantId(), subject, email, emailVerified)
+	if err != nil {
+		return nil, err
+	}
+	created := user == nil
+	if created {
+		user, err = m.createUser(ctx, tx, req, entry, email, emailVerified)
+		if err != nil {
+			return nil, err
+		}
+	} else if !common.IsUserValid(user.Status) {
+		return nil, ecode.ErrUserDisabled
+	}
+	if needAuth {
+		userAuth := &model.UserAuth{
+			TenantID: req.GetTenantId(),
+			UID:      user.ID,
+			Type:     int16(enumsv1.SignInType_SIGN_IN_TYPE_LDAP),
+			Status:   int16(enumsv1.AuthStatus_AUTH_STATUS_OK),
+			Appid:    m.url,
+			OpenID:   subject,
+		}
+		if err := tx.UserAuth.WithContext(ctx).Create(userAuth); err != nil {
+			return nil, err
+		}
+	}
+	return &userv1.SignInResult{
+		User:       common.UserModelToUser(user),
+		Identifier: param.Username,
+		Created:    created,
+	}, nil
+}
+
+// subject 目录中的用户标识，DN不区分大小写，统一转为小写
+// 配置的id属性（e.g. objectGUID）是二进制值，总是使用hex编码，避免部分值被当作字符串
+func (m *Manager) subject(entry *ldapsdk.Entry) string {
+	if m.mapping.id != "" {
+		return entry.GetHex(m.mapping.id)
+	}
+	return strings.ToLower(entry.DN)
+}
+
+// getUser 同一个目录可能被多个租户使用，查找已绑定的身份时限定租户
+// 未绑定时，配置了信任目录邮箱且和租户下已验证的邮箱一致则关联到该账号
+func (m *Manager) getUser(ctx context.Context, tx *query.Query, tenantID, subject, email string, emailVerified bool) (user *model.UserAccount, needCreateAuth bool, err error) {
+	q := tx.UserAuth
+	accountQ := tx.UserAccount
+	userAuth, err := q.WithContext(ctx).Where(
+		q.TenantID.Eq(tenantID),
+		q.Appid.Eq(m.url),
+		q.OpenID.Eq(subject),
+		q.Type.Eq(int16(enumsv1.SignInType_SIGN_IN_TYPE_LDAP)),
+	).Take()
+	if err == nil {
+		if userAuth.Status != int16(enumsv1.AuthStatus_AUTH_STATUS_OK) {
+			return nil, false, ecode.ErrUserAuthInvalid
+		}
+		user, err = accountQ.WithContext(ctx).Where(accountQ.TenantID.Eq(tenantID), accountQ.ID.Eq(userAuth.UID)).Take()
+		return user, false, err
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+	if !emailVerified {
+		return nil, true, nil
+	}
+	user, err = accountQ.WithContext(ctx).Where(
+		accountQ.TenantID.Eq(tenantID),
+		accountQ.Email.Eq(email),
+		accountQ.EmailVerified.Is(true),
+	).Take()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+	return user, true, nil
+}
+
+func (m *Manager) createUser(
+	ctx context.Context,
+	tx *query.Query,
+	req *userv1.SignInReq,
+	entry *ldapsdk.Entry,
+	email string,
+	emailVerified bool,
+) (*model.UserAccount, error) {
+	number, err := m.idService.GetShortID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := m.idService.GetGlobalID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	agent := req.GetAgent()
+	if agent == nil {
+		agent = &userv1.Agent{}
+	}
+	user := &model.UserAccount{
+		ID:               id,
+		TenantID:         req.GetTenantId(),
+		Number:           number,
+		Phone:            common.PlaceholderPhone(id),
+		Email:            common.PlaceholderEmail(id),
+		Name:             attrPtr(entry, m.mapping.name),
+		Alias_:           attrPtr(entry, m.mapping.alias),
+		Status:           int16(enumsv1.UserStatus_USER_STATUS_OK),
+		Source:           int16(enumsv1.UserSource_USER_SOURCE_WEB),
+		SignupType:       int16(enumsv1.SignUpType_SIGN_UP_TYPE_LDAP),
+		RegisterIP:       agent.Ip,
+		RegisterDevice:   agent.Device,
+		RegisterAgent:    agent.Agent,
+		RegisterLocation: common.LocationToString(agent.Location),
+	}
+	// 目录中的邮箱默认不可信，只有配置了trust_email并且租户下未被占用时才使用
+	if emailVerified {
+		accountQ := tx.UserAccount
+		count, err := accountQ.WithContext(ctx).Where(accountQ.TenantID.Eq(req.GetTenantId()), accountQ.Email.Eq(email)).Count()
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			user.Email = email
+			user.EmailVerified = true
+		}
+	}
+	if err := tx.UserAccount.WithContext(ctx).Create(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func attrPtr(entry *ldapsdk.Entry, name string) *string {
+	v := entry.Get(name)
+	if v == "" {
+		return nil
+	}
+	return &v
+}
@@ -0,0 +1,284 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/byteflowing/base/app/user/common"
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/ecode"
+	oidcsdk "github.com/byteflowing/base/pkg/oidc"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+// 标准claims，文档：https://openid.net/specs/openid-connect-core-1_0.html#StandardClaims
+const (
+	defaultSubjectClaim       = "sub"
+	defaultEmailClaim         = "email"
+	defaultEmailVerifiedClaim = "email_verified"
+	defaultNameClaim          = "name"
+	defaultAliasClaim         = "nickname"
+	defaultAvatarClaim        = "picture"
+)
+
+type claimMapping struct {
+	subject       string
+	email         string
+	emailVerified string
+	name          string
+	alias         string
+	avatar        string
+}
+
+// Manager 通用的OpenID Connect登录方式，任何兼容OIDC的IdP都可以通过配置接入
+// 不支持discovery的纯OAuth2 IdP可以直接配置端点，用户信息从userinfo获取
+type Manager struct {
+	clientID   string
+	trustEmail bool
+	mapping    *claimMapping
+	idService  *common.IDService
+	cli        *oidcsdk.Client
+}
+
+func NewManager(idService *common.IDService, config *userv1.OidcConfig) *Manager {
+	cli := oidcsdk.New(&oidcsdk.Config{
+		Issuer:       config.Issuer,
+		ClientID:     config.ClientId,
+		ClientSecret: config.ClientSecret,
+		RedirectURL:  config.RedirectUrl,
+		Scopes:       config.Scopes,
+		JwksTTL:      config.JwksTtl.AsDuration(),
+		AuthURL:      config.AuthUrl,
+		TokenURL:     config.TokenUrl,
+		UserInfoURL:  config.UserinfoUrl,
+		JwksURL:      config.JwksUrl,
+	})
+	return &Manager{
+		clientID:   config.ClientId,
+		trustEmail: config.TrustEmail,
+		mapping:    newClaimMapping(config.ClaimMapping),
+		idService:  idService,
+		cli:        cli,
+	}
+}
+
+func newClaimMapping(m *userv1.OidcClaimMapping) *claimMapping {
+	mapping := &claimMapping{
+		subject:       defaultSubjectClaim,
+		email:         defaultEmailClaim,
+		emailVerified: defaultEmailVerifiedClaim,
+		name:          defaultNameClaim,
+		alias:         defaultAliasClaim,
+		avatar:        defaultAvatarClaim,
+	}
+	if m == nil {
+		return mapping
+	}
+	set := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	set(&mapping.subject, m.Subject)
+	set(&mapping.email, m.Email)
+	set(&mapping.emailVerified, m.EmailVerified)
+	set(&mapping.name, m.Name)
+	set(&mapping.alias, m.Alias)
+	set(&mapping.avatar, m.Avatar)
+	return mapping
+}
+
+// AuthCodeURL 生成IdP的授权地址，客户端生成PKCE和nonce后跳转
+func (m *Manager) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	return m.cli.AuthCodeURL(ctx, state, nonce, codeChallenge)
+}
+
+func (m *Manager) Close() error {
+	return m.cli.Close()
+}
+
+func (m *Manager) Authenticate(ctx context.Context, req *userv1.SignInReq, tx *query.Query) (*userv1.SignInResult, error) {
+	if req == nil || req.SignInType != enumsv1.SignInType_SIGN_IN_TYPE_OIDC {
+		return nil, errors.New("invalid params")
+	}
+	param := req.GetOidc()
+	if param == nil || param.Code == "" {
+		return nil, ecode.ErrParams
+	}
+	token, err := m.cli.Exchange(ctx, param.Code, param.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := m.cli.Claims(ctx, token, param.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	subject := claimString(claims, m.mapping.subject)
+	if subject == "" {
+		return nil, ecode.ErrUserAuthInvalid
+	}
+	email := common.NormalizeEmail(claimString(claims, m.mapping.email))
+	// IdP声明的email_verified默认不可信，只有配置了trust_email时才用于关联或者写入账号
+	emailVerified := m.trustEmail && email != "" && claimBool(claims, m.mapping.emailVerified)
+	user, needAuth, err := m.getUser(ctx, tx, req.GetTenantId(), subject, email, emailVerified)
+	if err != nil {
+		return nil, err
+	}
+	created := user == nil
+	if created {
+		user, err = m.createUser(ctx, tx, req, claims, email, emailVerified)
+		if err != nil {
+			return nil, err
+		}
+	} else if !common.IsUserValid(user.Status) {
+		return nil, ecode.ErrUserDisabled
+	}
+	if needAuth {
+		userAuth := &model.UserAuth{
+			TenantID: req.GetTenantId(),
+			UID:      user.ID,
+			Type:     int16(enumsv1.SignInType_SIGN_IN_TYPE_OIDC),
+			Status:   int16(enumsv1.AuthStatus_AUTH_STATUS_OK),
+			Appid:    m.clientID,
+			OpenID:   subject,
+		}
+		if err := tx.UserAuth.WithContext(ctx).Create(userAuth); err != nil {
+			return nil, err
+		}
+	}
+	return &userv1.SignInResult{
+		User:       common.UserModelToUser(user),
+		Identifier: subject,
+		Created:    created,
+	}, nil
+}
+
+// getUser 同一个IdP可能被多个租户使用，查找已绑定的身份时限定租户
+// 未绑定时，配置了信任IdP邮箱且和租户下已验证的邮箱一致则关联到该账号
+func (m *Manager) getUser(ctx context.Context, tx *query.Query, tenantID, subject, email string, emailVerified bool) (user *model.UserAccount, needCreateAuth bool, err error) {
+	q := tx.UserAuth
+	accountQ := tx.UserAccount
+	userAuth, err := q.WithContext(ctx).Where(
+		q.TenantID.Eq(tenantID),
+		q.Appid.Eq(m.clientID),
+		q.OpenID.Eq(subject),
+		q.Type.Eq(int16(enumsv1.SignInType_SIGN_IN_TYPE_OIDC)),
+	).Take()
+	if err == nil {
+		if userAuth.Status != int16(enumsv1.AuthStatus_AUTH_STATUS_OK) {
+			return nil, false, ecode.ErrUserAuthInvalid
+		}
+		user, err = accountQ.WithContext(ctx).Where(accountQ.TenantID.Eq(tenantID), accountQ.ID.Eq(userAuth.UID)).Take()
+		return user, false, err
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+	if !emailVerified {
+		return nil, true, nil
+	}
+	user, err = accountQ.WithContext(ctx).Where(
+		accountQ.TenantID.Eq(tenantID),
+		accountQ.Email.Eq(email),
+		accountQ.EmailVerified.Is(true),
+	).Take()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+	return user, true, nil
+}
+
+func (m *Manager) createUser(
+	ctx context.Context,
+	tx *query.Query,
+	req *userv1.SignInReq,
+	claims map[string]any,
+	email string,
+	emailVerified bool,
+) (*model.UserAccount, error) {
+	number, err := m.idService.GetShortID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := m.idService.GetGlobalID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	agent := req.GetAgent()
+	if agent == nil {
+		agent = &userv1.Agent{}
+	}
+	user := &model.UserAccount{
+		ID:               id,
+		TenantID:         req.GetTenantId(),
+		Number:           number,
+		Phone:            common.PlaceholderPhone(id),
+		Email:            common.PlaceholderEmail(id),
+		Name:             claimStringPtr(claims, m.mapping.name),
+		Alias_:           claimStringPtr(claims, m.mapping.alias),
+		Avatar:           claimStringPtr(claims, m.mapping.avatar),
+		Status:           int16(enumsv1.UserStatus_USER_STATUS_OK),
+		Source:           int16(enumsv1.UserSource_USER_SOURCE_WEB),
+		SignupType:       int16(enumsv1.SignUpType_SIGN_UP_TYPE_OIDC),
+		RegisterIP:       agent.Ip,
+		RegisterDevice:   agent.Device,
+		RegisterAgent:    agent.Agent,
+		RegisterLocation: common.LocationToString(agent.Location),
+	}
+	// 未经IdP验证或者未配置trust_email的邮箱可能属于他人，只使用可信且租户下未被占用的邮箱
+	if emailVerified {
+		accountQ := tx.UserAccount
+		count, err := accountQ.WithContext(ctx).Where(accountQ.TenantID.Eq(req.GetTenantId()), accountQ.Email.Eq(email)).Count()
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			user.Email = email
+			user.EmailVerified = true
+		}
+	}
+	if err := tx.UserAccount.WithContext(ctx).Create(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// claimString 获取字符串类型的claim，数字类型的id（e.g. GitHub的id）会转成字符串
+func claimString(claims map[string]any, key string) string {
+	switch v := claims[key].(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	default:
+		return ""
+	}
+}
+
+func claimStringPtr(claims map[string]any, key string) *string {
+	v := claimString(claims, key)
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+// claimBool 部分IdP的email_verified是字符串"true"
+func claimBool(claims map[string]any, key string) bool {
+	switch v := claims[key].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
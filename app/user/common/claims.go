@@ -0,0 +1,181 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/pkg/utils/trans"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+// reservedClaims 由服务端写入的claim，自定义映射和请求中的extra claims都不能覆盖
+var reservedClaims = map[string]struct{}{
+	"iss": {}, "sub": {}, "exp": {}, "iat": {}, "nbf": {}, "jti": {},
+	JwtTokenTypeKey: {}, JwtTenantIDKey: {}, JwtNumberKey: {}, JwtTypeKey: {}, JwtLevelKey: {},
+	JwtRolesKey: {}, JwtScopeKey: {}, JwtActKey: {},
+}
+
+// claimColumns 可以映射到token中的user_account字段，密码等敏感字段不在其中
+var claimColumns = map[string]func(m *model.UserAccount) any{
+	"number":         func(m *model.UserAccount) any { return m.Number },
+	"name":           func(m *model.UserAccount) any { return ptrValue(m.Name) },
+	"alias":          func(m *model.UserAccount) any { return ptrValue(m.Alias_) },
+	"avatar":         func(m *model.UserAccount) any { return ptrValue(m.Avatar) },
+	"gender":         func(m *model.UserAccount) any { return ptrValue(m.Gender) },
+	"country_code":   func(m *model.UserAccount) any { return m.CountryCode },
+	"province_code":  func(m *model.UserAccount) any { return m.ProvinceCode },
+	"city_code":      func(m *model.UserAccount) any { return m.CityCode },
+	"district_code":  func(m *model.UserAccount) any { return m.DistrictCode },
+	"status":         func(m *model.UserAccount) any { return m.Status },
+	"source":         func(m *model.UserAccount) any { return m.Source },
+	"signup_type":    func(m *model.UserAccount) any { return m.SignupType },
+	"phone_verified": func(m *model.UserAccount) any { return m.PhoneVerified },
+	"email_verified": func(m *model.UserAccount) any { return m.EmailVerified },
+	"type":           func(m *model.UserAccount) any { return ptrValue(m.Type) },
+	"level":          func(m *model.UserAccount) any { return ptrValue(m.Level) },
+	"birthday": func(m *model.UserAccount) any {
+		if m.Birthday == nil {
+			return nil
+		}
+		return m.Birthday.Format("2006-01-02")
+	},
+}
+
+// ValidateClaimMappings 检查claim名称和数据来源，每个映射只能指定column和ext_key中的一个
+func ValidateClaimMappings(mappings []*userv1.ClaimMapping) bool {
+	for _, v := range mappings {
+		if v.Claim == "" || IsReservedClaim(v.Claim) {
+			return false
+		}
+		if (v.Column == "") == (v.ExtKey == "") {
+			return false
+		}
+		if _, ok := claimColumns[v.Column]; v.Column != "" && !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func IsReservedClaim(claim string) bool {
+	_, ok := reservedClaims[claim]
+	return ok
+}
+
+// MapUserClaims 按映射从账号字段或ext中取值，保留原始类型写入token
+// ext_key使用.分隔访问嵌套的对象，值不存在或为null时不写入
+func MapUserClaims(m *model.UserAccount, mappings []*userv1.ClaimMapping) map[string]any {
+	if len(mappings) == 0 {
+		return nil
+	}
+	var ext map[string]any
+	if s := trans.Deref(m.Ext); s != "" {
+		d := json.NewDecoder(strings.NewReader(s))
+		// 使用json.Number避免大整数转为float64后丢失精度
+		d.UseNumber()
+		_ = d.Decode(&ext)
+	}
+	claims := make(map[string]any, len(mappings))
+	for _, v := range mappings {
+		if IsReservedClaim(v.Claim) {
+			continue
+		}
+		var value any
+		if v.Column != "" {
+			if get, ok := claimColumns[v.Column]; ok {
+				value = get(m)
+			}
+		} else {
+			value = lookupExt(ext, v.ExtKey)
+		}
+		if value != nil {
+			claims[v.Claim] = value
+		}
+	}
+	return claims
+}
+
+func lookupExt(ext map[string]any, key string) any {
+	var cur any = ext
+	for _, k := range strings.Split(key, ".") {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur = obj[k]
+	}
+	return cur
+}
+
+func ptrValue[T any](p *T) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+// claimInt32 读取整数类型的claim
+// 解析后的token中数字为float64或json.Number，刚签发的token中为原始的整数类型，旧版本签发的token中为字符串
+func claimInt32(v any) (int32, bool) {
+	var i int64
+	switch n := v.(type) {
+	case float64:
+		if n != math.Trunc(n) {
+			return 0, false
+		}
+		i = int64(n)
+	case json.Number:
+		parsed, err := n.Int64()
+		if err != nil {
+			return 0, false
+		}
+		i = parsed
+	case string:
+		parsed, err := strconv.ParseInt(n, 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		i = parsed
+	case int:
+		i = int64(n)
+	case int16:
+		i = int64(n)
+	case int32:
+		i = int64(n)
+	case int64:
+		i = n
+	default:
+		return 0, false
+	}
+	if i < math.MinInt32 || i > math.MaxInt32 {
+		return 0, false
+	}
+	return int32(i), true
+}
+
+// claimString 把自定义claim转为字符串，数字和布尔值按字面量输出，对象和数组输出json
+func claimString(v any) (string, bool) {
+	switch s := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		return s, true
+	case json.Number:
+		return s.String(), true
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(s), true
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", false
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), true
+}
@@ -2,6 +2,10 @@ package common
 
 const (
 	locationFormat = "%f,%f"
+
+	// 手机号和邮箱上有唯一索引，没有手机号或邮箱的账号使用uid生成占位值
+	placeholderEmailFormat = "%d@placeholder.invalid"
+	placeholderEmailSuffix = ".invalid"
 )
 
 const (
@@ -11,4 +15,12 @@ const (
 	JwtLevelKey     = "level"
 	JwtJtiKey       = "jti"
 	JwtTokenTypeKey = "token_type"
+	JwtRolesKey     = "roles"
+	JwtScopeKey     = "scope"
+	// JwtActKey 模拟登录时的操作人，格式：{"sub": "<操作人uid>"}，见RFC 8693
+	JwtActKey = "act"
+
+	JwtSignInTypeKey = "sign_in_type"
+	JwtIdentifierKey = "identifier"
+	JwtSignLogIDKey  = "sign_log_id"
 )
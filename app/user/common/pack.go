@@ -30,6 +30,9 @@ func UserModelToUser(m *model.UserAccount) *userv1.User {
 		PhoneVerify: m.PhoneVerified,
 		EmailVerify: m.EmailVerified,
 	}
+	if IsPlaceholderEmail(m.Email) {
+		u.Email = ""
+	}
 	if m.Birthday != nil {
 		u.Birthday = &date.Date{
 			Year:  int32(m.Birthday.Year()),
@@ -78,29 +81,34 @@ func UserModelToUser(m *model.UserAccount) *userv1.User {
 func ClaimsToJwtClaims(claims jwt.MapClaims, extraKey []string) *userv1.JwtClaims {
 	iss, _ := claims.GetIssuer()
 	sub, _ := claims.GetSubject()
-	iat, _ := claims.GetIssuedAt()
-	nbf, _ := claims.GetNotBefore()
-	exp, _ := claims.GetExpirationTime()
+	unix := func(get func() (*jwt.NumericDate, error)) int64 {
+		d, err := get()
+		if err != nil || d == nil {
+			return 0
+		}
+		return d.Unix()
+	}
 	c := &userv1.JwtClaims{
 		Iss:       iss,
 		Sub:       sub,
-		Iat:       iat.Unix(),
-		Nbf:       nbf.Unix(),
-		Exp:       exp.Unix(),
+		Iat:       unix(claims.GetIssuedAt),
+		Nbf:       unix(claims.GetNotBefore),
+		Exp:       unix(claims.GetExpirationTime),
 		Jti:       GetJwtJti(claims),
 		TokenType: GetTokenType(claims),
 		TenantId:  GetTokenTenantID(claims),
 		Number:    GetTokenNumber(claims),
 		Type:      GetTokenUserType(claims),
 		Level:     GetTokenUserLevel(claims),
+		Roles:     GetTokenRoles(claims),
+		Scopes:    GetTokenScopes(claims),
+		ActorUid:  GetTokenActorUID(claims),
 	}
 	extra := make(map[string]string, len(extraKey))
 	for _, k := range extraKey {
-		v, ok := claims[k]
-		if !ok {
-			continue
+		if v, ok := claimString(claims[k]); ok {
+			extra[k] = v
 		}
-		extra[k] = v.(string)
 	}
 	c.Extra = extra
 	return c
@@ -2,6 +2,7 @@ package common
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -12,10 +13,27 @@ import (
 )
 
 func IsUserValid(st int16) bool {
-	if st != int16(enumsv1.UserStatus_USER_STATUS_DISABLED) {
-		return false
-	}
-	return true
+	return st == int16(enumsv1.UserStatus_USER_STATUS_OK)
+}
+
+// PlaceholderPhone 没有手机号的账号使用的占位手机号，区号为空，不会出现在用户信息中
+func PlaceholderPhone(uid int64) string {
+	return strconv.FormatInt(uid, 10)
+}
+
+// PlaceholderEmail 没有邮箱的账号使用的占位邮箱
+func PlaceholderEmail(uid int64) string {
+	return fmt.Sprintf(placeholderEmailFormat, uid)
+}
+
+// NormalizeEmail 邮箱写入和查询前统一去掉空白并转为小写，避免同一邮箱因为大小写不同被重复注册
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// IsPlaceholderEmail 使用保留域名.invalid的邮箱都是占位邮箱
+func IsPlaceholderEmail(email string) bool {
+	return strings.HasSuffix(email, placeholderEmailSuffix)
 }
 
 func LocationToString(location *typesv1.Location) *string {
@@ -39,6 +57,17 @@ func ParseLocationFromString(location *string) *typesv1.Location {
 	}
 }
 
+// DistanceKm 使用haversine公式计算两个经纬度之间的球面距离，单位千米
+func DistanceKm(a, b *typesv1.Location) float64 {
+	const earthRadiusKm = 6371.0
+	toRad := func(d float64) float64 { return d * math.Pi / 180 }
+	dLat := toRad(b.Lat - a.Lat)
+	dLng := toRad(b.Lng - a.Lng)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(a.Lat))*math.Cos(toRad(b.Lat))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
 func GetJwtJti(claims jwt.MapClaims) string {
 	jti, ok := claims[JwtJtiKey].(string)
 	if !ok {
@@ -47,6 +76,14 @@ func GetJwtJti(claims jwt.MapClaims) string {
 	return jti
 }
 
+func GetJwtUID(claims jwt.MapClaims) (int64, error) {
+	sub, err := claims.GetSubject()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(sub, 10, 64)
+}
+
 func GetJwtExp(claims jwt.MapClaims) *time.Time {
 	exp, err := claims.GetExpirationTime()
 	if err != nil {
@@ -56,6 +93,19 @@ func GetJwtExp(claims jwt.MapClaims) *time.Time {
 	return &expiredAt
 }
 
+// GetMfaSignInInfo 获取mfa token中记录的登录方式及登录标识
+func GetMfaSignInInfo(claims jwt.MapClaims) (signInType enumsv1.SignInType, identifier string) {
+	t, _ := claims[JwtSignInTypeKey].(string)
+	identifier, _ = claims[JwtIdentifierKey].(string)
+	return enumsv1.SignInType(enumsv1.SignInType_value[t]), identifier
+}
+
+// GetTokenSignLogID 获取撤销登录链接中的登录日志id
+func GetTokenSignLogID(claims jwt.MapClaims) string {
+	id, _ := claims[JwtSignLogIDKey].(string)
+	return id
+}
+
 func GetTokenType(claims jwt.MapClaims) enumsv1.TokenType {
 	t, _ := claims[JwtTokenTypeKey].(string)
 	return enumsv1.TokenType(enumsv1.TokenType_value[t])
@@ -72,27 +122,55 @@ func GetTokenNumber(claims jwt.MapClaims) string {
 }
 
 func GetTokenUserType(claims jwt.MapClaims) *int32 {
-	userType, ok := claims[JwtTypeKey].(string)
+	t, ok := claimInt32(claims[JwtTypeKey])
 	if !ok {
 		return nil
 	}
-	t, err := strconv.ParseInt(userType, 10, 32)
-	if err != nil {
+	return &t
+}
+
+func GetTokenUserLevel(claims jwt.MapClaims) *int32 {
+	l, ok := claimInt32(claims[JwtLevelKey])
+	if !ok {
 		return nil
 	}
-	i32t := int32(t)
-	return &i32t
+	return &l
 }
 
-func GetTokenUserLevel(claims jwt.MapClaims) *int32 {
-	userLevel, ok := claims[JwtLevelKey].(string)
+// GetTokenRoles 获取token中的角色名，jwt解析后数组类型为[]any
+func GetTokenRoles(claims jwt.MapClaims) []string {
+	values, ok := claims[JwtRolesKey].([]any)
 	if !ok {
 		return nil
 	}
-	l, err := strconv.ParseInt(userLevel, 10, 32)
+	roles := make([]string, 0, len(values))
+	for _, v := range values {
+		if role, ok := v.(string); ok {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// GetTokenScopes 获取服务账号token中的scope，多个scope以空格分隔
+func GetTokenScopes(claims jwt.MapClaims) []string {
+	scope, _ := claims[JwtScopeKey].(string)
+	return strings.Fields(scope)
+}
+
+// GetTokenActorUID 获取模拟登录token中操作人的uid，不是模拟登录的token返回0
+func GetTokenActorUID(claims jwt.MapClaims) int64 {
+	act, ok := claims[JwtActKey].(map[string]any)
+	if !ok {
+		return 0
+	}
+	sub, ok := claimString(act["sub"])
+	if !ok {
+		return 0
+	}
+	uid, err := strconv.ParseInt(sub, 10, 64)
 	if err != nil {
-		return nil
+		return 0
 	}
-	i32l := int32(l)
-	return &i32l
+	return uid
 }
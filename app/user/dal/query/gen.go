@@ -17,29 +17,83 @@ import (
 
 func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 	return &Query{
-		db:          db,
-		UserAccount: newUserAccount(db, opts...),
-		UserAuth:    newUserAuth(db, opts...),
-		UserSignLog: newUserSignLog(db, opts...),
+		db:                  db,
+		UserAccount:         newUserAccount(db, opts...),
+		UserApiKey:          newUserApiKey(db, opts...),
+		UserAuth:            newUserAuth(db, opts...),
+		UserDeletion:        newUserDeletion(db, opts...),
+		UserDevice:          newUserDevice(db, opts...),
+		UserImpersonation:   newUserImpersonation(db, opts...),
+		UserInviteCode:      newUserInviteCode(db, opts...),
+		UserOutbox:          newUserOutbox(db, opts...),
+		UserPasswordHistory: newUserPasswordHistory(db, opts...),
+		UserRecoveryCode:    newUserRecoveryCode(db, opts...),
+		UserReferral:        newUserReferral(db, opts...),
+		UserRole:            newUserRole(db, opts...),
+		UserRoleBinding:     newUserRoleBinding(db, opts...),
+		UserRolePermission:  newUserRolePermission(db, opts...),
+		UserScimToken:       newUserScimToken(db, opts...),
+		UserScimUser:        newUserScimUser(db, opts...),
+		UserServiceAccount:  newUserServiceAccount(db, opts...),
+		UserSignLog:         newUserSignLog(db, opts...),
+		UserSignLogArchive:  newUserSignLogArchive(db, opts...),
+		UserTenant:          newUserTenant(db, opts...),
+		UserTotp:            newUserTotp(db, opts...),
 	}
 }
 
 type Query struct {
 	db *gorm.DB
 
-	UserAccount userAccount
-	UserAuth    userAuth
-	UserSignLog userSignLog
+	UserAccount         userAccount
+	UserApiKey          userApiKey
+	UserAuth            userAuth
+	UserDeletion        userDeletion
+	UserDevice          userDevice
+	UserImpersonation   userImpersonation
+	UserInviteCode      userInviteCode
+	UserOutbox          userOutbox
+	UserPasswordHistory userPasswordHistory
+	UserRecoveryCode    userRecoveryCode
+	UserReferral        userReferral
+	UserRole            userRole
+	UserRoleBinding     userRoleBinding
+	UserRolePermission  userRolePermission
+	UserScimToken       userScimToken
+	UserScimUser        userScimUser
+	UserServiceAccount  userServiceAccount
+	UserSignLog         userSignLog
+	UserSignLogArchive  userSignLogArchive
+	UserTenant          userTenant
+	UserTotp            userTotp
 }
 
 func (q *Query) Available() bool { return q.db != nil }
 
 func (q *Query) clone(db *gorm.DB) *Query {
 	return &Query{
-		db:          db,
-		UserAccount: q.UserAccount.clone(db),
-		UserAuth:    q.UserAuth.clone(db),
-		UserSignLog: q.UserSignLog.clone(db),
+		db:                  db,
+		UserAccount:         q.UserAccount.clone(db),
+		UserApiKey:          q.UserApiKey.clone(db),
+		UserAuth:            q.UserAuth.clone(db),
+		UserDeletion:        q.UserDeletion.clone(db),
+		UserDevice:          q.UserDevice.clone(db),
+		UserImpersonation:   q.UserImpersonation.clone(db),
+		UserInviteCode:      q.UserInviteCode.clone(db),
+		UserOutbox:          q.UserOutbox.clone(db),
+		UserPasswordHistory: q.UserPasswordHistory.clone(db),
+		UserRecoveryCode:    q.UserRecoveryCode.clone(db),
+		UserReferral:        q.UserReferral.clone(db),
+		UserRole:            q.UserRole.clone(db),
+		UserRoleBinding:     q.UserRoleBinding.clone(db),
+		UserRolePermission:  q.UserRolePermission.clone(db),
+		UserScimToken:       q.UserScimToken.clone(db),
+		UserScimUser:        q.UserScimUser.clone(db),
+		UserServiceAccount:  q.UserServiceAccount.clone(db),
+		UserSignLog:         q.UserSignLog.clone(db),
+		UserSignLogArchive:  q.UserSignLogArchive.clone(db),
+		UserTenant:          q.UserTenant.clone(db),
+		UserTotp:            q.UserTotp.clone(db),
 	}
 }
 
@@ -53,24 +107,78 @@ func (q *Query) WriteDB() *Query {
 
 func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 	return &Query{
-		db:          db,
-		UserAccount: q.UserAccount.replaceDB(db),
-		UserAuth:    q.UserAuth.replaceDB(db),
-		UserSignLog: q.UserSignLog.replaceDB(db),
+		db:                  db,
+		UserAccount:         q.UserAccount.replaceDB(db),
+		UserApiKey:          q.UserApiKey.replaceDB(db),
+		UserAuth:            q.UserAuth.replaceDB(db),
+		UserDeletion:        q.UserDeletion.replaceDB(db),
+		UserDevice:          q.UserDevice.replaceDB(db),
+		UserImpersonation:   q.UserImpersonation.replaceDB(db),
+		UserInviteCode:      q.UserInviteCode.replaceDB(db),
+		UserOutbox:          q.UserOutbox.replaceDB(db),
+		UserPasswordHistory: q.UserPasswordHistory.replaceDB(db),
+		UserRecoveryCode:    q.UserRecoveryCode.replaceDB(db),
+		UserReferral:        q.UserReferral.replaceDB(db),
+		UserRole:            q.UserRole.replaceDB(db),
+		UserRoleBinding:     q.UserRoleBinding.replaceDB(db),
+		UserRolePermission:  q.UserRolePermission.replaceDB(db),
+		UserScimToken:       q.UserScimToken.replaceDB(db),
+		UserScimUser:        q.UserScimUser.replaceDB(db),
+		UserServiceAccount:  q.UserServiceAccount.replaceDB(db),
+		UserSignLog:         q.UserSignLog.replaceDB(db),
+		UserSignLogArchive:  q.UserSignLogArchive.replaceDB(db),
+		UserTenant:          q.UserTenant.replaceDB(db),
+		UserTotp:            q.UserTotp.replaceDB(db),
 	}
 }
 
 type queryCtx struct {
-	UserAccount IUserAccountDo
-	UserAuth    IUserAuthDo
-	UserSignLog IUserSignLogDo
+	UserAccount         IUserAccountDo
+	UserApiKey          IUserApiKeyDo
+	UserAuth            IUserAuthDo
+	UserDeletion        IUserDeletionDo
+	UserDevice          IUserDeviceDo
+	UserImpersonation   IUserImpersonationDo
+	UserInviteCode      IUserInviteCodeDo
+	UserOutbox          IUserOutboxDo
+	UserPasswordHistory IUserPasswordHistoryDo
+	UserRecoveryCode    IUserRecoveryCodeDo
+	UserReferral        IUserReferralDo
+	UserRole            IUserRoleDo
+	UserRoleBinding     IUserRoleBindingDo
+	UserRolePermission  IUserRolePermissionDo
+	UserScimToken       IUserScimTokenDo
+	UserScimUser        IUserScimUserDo
+	UserServiceAccount  IUserServiceAccountDo
+	UserSignLog         IUserSignLogDo
+	UserSignLogArchive  IUserSignLogArchiveDo
+	UserTenant          IUserTenantDo
+	UserTotp            IUserTotpDo
 }
 
 func (q *Query) WithContext(ctx context.Context) *queryCtx {
 	return &queryCtx{
-		UserAccount: q.UserAccount.WithContext(ctx),
-		UserAuth:    q.UserAuth.WithContext(ctx),
-		UserSignLog: q.UserSignLog.WithContext(ctx),
+		UserAccount:         q.UserAccount.WithContext(ctx),
+		UserApiKey:          q.UserApiKey.WithContext(ctx),
+		UserAuth:            q.UserAuth.WithContext(ctx),
+		UserDeletion:        q.UserDeletion.WithContext(ctx),
+		UserDevice:          q.UserDevice.WithContext(ctx),
+		UserImpersonation:   q.UserImpersonation.WithContext(ctx),
+		UserInviteCode:      q.UserInviteCode.WithContext(ctx),
+		UserOutbox:          q.UserOutbox.WithContext(ctx),
+		UserPasswordHistory: q.UserPasswordHistory.WithContext(ctx),
+		UserRecoveryCode:    q.UserRecoveryCode.WithContext(ctx),
+		UserReferral:        q.UserReferral.WithContext(ctx),
+		UserRole:            q.UserRole.WithContext(ctx),
+		UserRoleBinding:     q.UserRoleBinding.WithContext(ctx),
+		UserRolePermission:  q.UserRolePermission.WithContext(ctx),
+		UserScimToken:       q.UserScimToken.WithContext(ctx),
+		UserScimUser:        q.UserScimUser.WithContext(ctx),
+		UserServiceAccount:  q.UserServiceAccount.WithContext(ctx),
+		UserSignLog:         q.UserSignLog.WithContext(ctx),
+		UserSignLogArchive:  q.UserSignLogArchive.WithContext(ctx),
+		UserTenant:          q.UserTenant.WithContext(ctx),
+		UserTotp:            q.UserTotp.WithContext(ctx),
 	}
 }
 
@@ -30,6 +30,24 @@ func main() {
 		g.GenerateModelAs("user_account", "UserAccount"),
 		g.GenerateModelAs("user_auth", "UserAuth"),
 		g.GenerateModelAs("user_sign_log", "UserSignLog"),
+		g.GenerateModelAs("user_totp", "UserTotp"),
+		g.GenerateModelAs("user_recovery_code", "UserRecoveryCode"),
+		g.GenerateModelAs("user_deletion", "UserDeletion"),
+		g.GenerateModelAs("user_tenant", "UserTenant"),
+		g.GenerateModelAs("user_role", "UserRole"),
+		g.GenerateModelAs("user_role_permission", "UserRolePermission"),
+		g.GenerateModelAs("user_role_binding", "UserRoleBinding"),
+		g.GenerateModelAs("user_sign_log_archive", "UserSignLogArchive"),
+		g.GenerateModelAs("user_service_account", "UserServiceAccount"),
+		g.GenerateModelAs("user_api_key", "UserApiKey"),
+		g.GenerateModelAs("user_outbox", "UserOutbox"),
+		g.GenerateModelAs("user_device", "UserDevice"),
+		g.GenerateModelAs("user_invite_code", "UserInviteCode"),
+		g.GenerateModelAs("user_referral", "UserReferral"),
+		g.GenerateModelAs("user_password_history", "UserPasswordHistory"),
+		g.GenerateModelAs("user_scim_token", "UserScimToken"),
+		g.GenerateModelAs("user_scim_user", "UserScimUser"),
+		g.GenerateModelAs("user_impersonation", "UserImpersonation"),
 	)
 	g.Execute()
 }
@@ -0,0 +1,141 @@
+// Package introspect
+// RFC 7662 token自省的HTTP接口，供不使用grpc的资源服务器校验token
+// 调用方使用具有token:introspect scope的服务账号token认证：Authorization: Bearer xxx
+package introspect
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/byteflowing/base/app/user/service"
+	"github.com/byteflowing/base/pkg/logx"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+// Path 自省接口的挂载路径
+const Path = "/oauth2/introspect"
+
+// 请求体的大小上限
+const maxBodyBytes = 64 << 10
+
+// response RFC 7662 2.2，token无效时只返回active=false
+type response struct {
+	Active    bool     `json:"active"`
+	Scope     string   `json:"scope,omitempty"`
+	TokenType string   `json:"token_type,omitempty"`
+	Exp       int64    `json:"exp,omitempty"`
+	Iat       int64    `json:"iat,omitempty"`
+	Nbf       int64    `json:"nbf,omitempty"`
+	Sub       string   `json:"sub,omitempty"`
+	Iss       string   `json:"iss,omitempty"`
+	Jti       string   `json:"jti,omitempty"`
+	TenantID  string   `json:"tenant_id,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+	ActorUID  int64    `json:"actor_uid,omitempty"`
+}
+
+type errorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+type Handler struct {
+	svc *service.UserService
+}
+
+func NewHandler(svc *service.UserService) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, &errorResponse{Error: "invalid_request", ErrorDescription: "method not allowed"})
+		return
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || strings.TrimSpace(token) == "" {
+		writeUnauthorized(w, "missing bearer token")
+		return
+	}
+	if err := h.svc.AuthenticateIntrospectionClient(r.Context(), strings.TrimSpace(token)); err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.PermissionDenied {
+			writeUnauthorized(w, st.Message())
+			return
+		}
+		writeInternalError(w, err)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("token") == "" {
+		writeJSON(w, http.StatusBadRequest, &errorResponse{Error: "invalid_request", ErrorDescription: "token is required"})
+		return
+	}
+	resp, err := h.svc.IntrospectToken(r.Context(), &userv1.IntrospectTokenReq{
+		Token:         r.PostForm.Get("token"),
+		TokenTypeHint: r.PostForm.Get("token_type_hint"),
+	})
+	if err != nil {
+		writeInternalError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, introspectRespToResponse(resp))
+}
+
+func introspectRespToResponse(resp *userv1.IntrospectTokenResp) *response {
+	if !resp.Active {
+		return &response{}
+	}
+	out := &response{Active: true, Scope: resp.Scope}
+	if c := resp.Claims; c != nil {
+		out.TokenType = tokenTypeName(c.TokenType)
+		out.Exp = c.Exp
+		out.Iat = c.Iat
+		out.Nbf = c.Nbf
+		out.Sub = c.Sub
+		out.Iss = c.Iss
+		out.Jti = c.Jti
+		out.TenantID = c.TenantId
+		out.Roles = c.Roles
+		out.ActorUID = c.ActorUid
+	}
+	return out
+}
+
+// tokenTypeName 和token_type_hint使用相同的名称
+func tokenTypeName(typ enumsv1.TokenType) string {
+	switch typ {
+	case enumsv1.TokenType_TOKEN_TYPE_ACCESS:
+		return "access_token"
+	case enumsv1.TokenType_TOKEN_TYPE_REFRESH:
+		return "refresh_token"
+	}
+	return typ.String()
+}
+
+// writeUnauthorized 调用方认证失败，RFC 7662 2.3要求返回401
+func writeUnauthorized(w http.ResponseWriter, description string) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	writeJSON(w, http.StatusUnauthorized, &errorResponse{Error: "invalid_client", ErrorDescription: description})
+}
+
+func writeInternalError(w http.ResponseWriter, err error) {
+	logx.Error("token introspection failed", zap.Error(err))
+	writeJSON(w, http.StatusInternalServerError, &errorResponse{Error: "server_error"})
+}
+
+// writeJSON 自省结果不能被缓存
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logx.Error("write introspection response failed", zap.Error(err))
+	}
+}
@@ -21,5 +21,23 @@ func (m *Migrate) MigrateDB() (err error) {
 		&model.UserAccount{},
 		&model.UserAuth{},
 		&model.UserSignLog{},
+		&model.UserTotp{},
+		&model.UserRecoveryCode{},
+		&model.UserDeletion{},
+		&model.UserTenant{},
+		&model.UserRole{},
+		&model.UserRolePermission{},
+		&model.UserRoleBinding{},
+		&model.UserSignLogArchive{},
+		&model.UserServiceAccount{},
+		&model.UserApiKey{},
+		&model.UserOutbox{},
+		&model.UserDevice{},
+		&model.UserInviteCode{},
+		&model.UserReferral{},
+		&model.UserPasswordHistory{},
+		&model.UserScimToken{},
+		&model.UserScimUser{},
+		&model.UserImpersonation{},
 	)
 }
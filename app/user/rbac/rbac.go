@@ -0,0 +1,84 @@
+package rbac
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/byteflowing/base/ecode"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+const (
+	permissionSep      = ":"
+	permissionWildcard = "*"
+)
+
+type claimsKey struct{}
+
+// NewContext 将校验通过的jwt claims放入context，一般在拦截器中调用ValidateToken之后设置
+func NewContext(ctx context.Context, claims *userv1.JwtClaims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// FromContext 从context中取出jwt claims
+func FromContext(ctx context.Context) (*userv1.JwtClaims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*userv1.JwtClaims)
+	return claims, ok && claims != nil
+}
+
+// HasRole 判断context中的用户是否拥有指定角色
+func HasRole(ctx context.Context, role string) bool {
+	claims, ok := FromContext(ctx)
+	if !ok {
+		return false
+	}
+	return slices.Contains(claims.Roles, role)
+}
+
+// MatchPermission 判断授予的权限是否覆盖需要的权限
+// 权限使用冒号分段，e.g. order:read，"*"表示所有权限，"order:*"表示order下的所有权限
+func MatchPermission(granted, required string) bool {
+	if granted == permissionWildcard || granted == required {
+		return true
+	}
+	prefix, ok := strings.CutSuffix(granted, permissionSep+permissionWildcard)
+	if !ok {
+		return false
+	}
+	return strings.HasPrefix(required, prefix+permissionSep)
+}
+
+// Checker 供其他服务使用，根据context中的claims调用用户服务的CheckPermission
+type Checker struct {
+	cli userv1.UserServiceClient
+}
+
+func NewChecker(cli userv1.UserServiceClient) *Checker {
+	return &Checker{cli: cli}
+}
+
+// CheckPermission context中没有claims时返回ErrUserTokenInvalid，没有权限时返回ErrUserPermissionDenied
+func (c *Checker) CheckPermission(ctx context.Context, permission string) error {
+	claims, ok := FromContext(ctx)
+	if !ok {
+		return ecode.ErrUserTokenInvalid
+	}
+	uid, err := strconv.ParseInt(claims.Sub, 10, 64)
+	if err != nil {
+		return ecode.ErrUserTokenInvalid
+	}
+	resp, err := c.cli.CheckPermission(ctx, &userv1.CheckPermissionReq{
+		Uid:        uid,
+		TenantId:   claims.TenantId,
+		Permission: permission,
+	})
+	if err != nil {
+		return err
+	}
+	if !resp.Allowed {
+		return ecode.ErrUserPermissionDenied
+	}
+	return nil
+}
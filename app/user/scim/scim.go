@@ -0,0 +1,324 @@
+// Package scim
+// SCIM 2.0 的HTTP接口，企业租户的HR系统或IdP通过它同步用户和用户组
+// 请求使用租户的SCIM token认证：Authorization: Bearer scim_xxx
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/byteflowing/base/app/user/service"
+	"github.com/byteflowing/base/pkg/logx"
+	scimsdk "github.com/byteflowing/base/pkg/scim"
+)
+
+// BasePath SCIM接口的挂载路径
+const BasePath = "/scim/v2"
+
+// 请求体的大小上限
+const maxBodyBytes = 1 << 20
+
+type tenantKey struct{}
+
+type Handler struct {
+	svc *service.UserService
+	mux *http.ServeMux
+}
+
+func NewHandler(svc *service.UserService) *Handler {
+	h := &Handler{svc: svc, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /ServiceProviderConfig", h.serviceProviderConfig)
+	h.mux.HandleFunc("GET /Users", h.listUsers)
+	h.mux.HandleFunc("POST /Users", h.createUser)
+	h.mux.HandleFunc("GET /Users/{id}", h.getUser)
+	h.mux.HandleFunc("PUT /Users/{id}", h.replaceUser)
+	h.mux.HandleFunc("PATCH /Users/{id}", h.patchUser)
+	h.mux.HandleFunc("DELETE /Users/{id}", h.deleteUser)
+	h.mux.HandleFunc("GET /Groups", h.listGroups)
+	h.mux.HandleFunc("POST /Groups", h.createGroup)
+	h.mux.HandleFunc("GET /Groups/{id}", h.getGroup)
+	h.mux.HandleFunc("PUT /Groups/{id}", h.replaceGroup)
+	h.mux.HandleFunc("PATCH /Groups/{id}", h.patchGroup)
+	h.mux.HandleFunc("DELETE /Groups/{id}", h.deleteGroup)
+	return h
+}
+
+// ServeHTTP 校验token后把租户放到context中，路径需要去掉BasePath前缀后再传入
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	token, ok := strings.CutPrefix(auth, "Bearer ")
+	if !ok || token == "" {
+		writeError(w, scimsdk.NewError(http.StatusUnauthorized, "", "missing bearer token"))
+		return
+	}
+	tenantID, err := h.svc.AuthenticateScimToken(r.Context(), strings.TrimSpace(token))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	h.mux.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), tenantKey{}, tenantID)))
+}
+
+func (h *Handler) serviceProviderConfig(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"schemas":        []string{"urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"},
+		"patch":          map[string]bool{"supported": true},
+		"bulk":           map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]any{"supported": true, "maxResults": 200},
+		"changePassword": map[string]bool{"supported": false},
+		"sort":           map[string]bool{"supported": false},
+		"etag":           map[string]bool{"supported": false},
+		"authenticationSchemes": []map[string]any{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer Token",
+			"description": "Per-tenant SCIM token",
+			"primary":     true,
+		}},
+	})
+}
+
+func (h *Handler) listUsers(w http.ResponseWriter, r *http.Request) {
+	startIndex, count, err := parsePage(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := h.svc.ScimListUsers(r.Context(), tenant(r), r.URL.Query().Get("filter"), startIndex, count)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	for _, item := range resp.Resources {
+		setUserLocation(r, item.(*scimsdk.User))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) createUser(w http.ResponseWriter, r *http.Request) {
+	in := &scimsdk.User{}
+	if err := decode(r, in); err != nil {
+		writeError(w, err)
+		return
+	}
+	user, err := h.svc.ScimCreateUser(r.Context(), tenant(r), in)
+	h.writeUser(w, r, http.StatusCreated, user, err)
+}
+
+func (h *Handler) getUser(w http.ResponseWriter, r *http.Request) {
+	user, err := h.svc.ScimGetUser(r.Context(), tenant(r), r.PathValue("id"))
+	h.writeUser(w, r, http.StatusOK, user, err)
+}
+
+func (h *Handler) replaceUser(w http.ResponseWriter, r *http.Request) {
+	in := &scimsdk.User{}
+	if err := decode(r, in); err != nil {
+		writeError(w, err)
+		return
+	}
+	user, err := h.svc.ScimReplaceUser(r.Context(), tenant(r), r.PathValue("id"), in)
+	h.writeUser(w, r, http.StatusOK, user, err)
+}
+
+func (h *Handler) patchUser(w http.ResponseWriter, r *http.Request) {
+	patch := &scimsdk.PatchOp{}
+	if err := decode(r, patch); err != nil {
+		writeError(w, err)
+		return
+	}
+	user, err := h.svc.ScimPatchUser(r.Context(), tenant(r), r.PathValue("id"), patch)
+	h.writeUser(w, r, http.StatusOK, user, err)
+}
+
+func (h *Handler) deleteUser(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.ScimDeleteUser(r.Context(), tenant(r), r.PathValue("id")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) listGroups(w http.ResponseWriter, r *http.Request) {
+	startIndex, count, err := parsePage(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	resp, err := h.svc.ScimListGroups(r.Context(), tenant(r), r.URL.Query().Get("filter"), startIndex, count)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	for _, item := range resp.Resources {
+		setGroupLocation(r, item.(*scimsdk.Group))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) createGroup(w http.ResponseWriter, r *http.Request) {
+	in := &scimsdk.Group{}
+	if err := decode(r, in); err != nil {
+		writeError(w, err)
+		return
+	}
+	group, err := h.svc.ScimCreateGroup(r.Context(), tenant(r), in)
+	h.writeGroup(w, r, http.StatusCreated, group, err)
+}
+
+func (h *Handler) getGroup(w http.ResponseWriter, r *http.Request) {
+	group, err := h.svc.ScimGetGroup(r.Context(), tenant(r), r.PathValue("id"))
+	h.writeGroup(w, r, http.StatusOK, group, err)
+}
+
+func (h *Handler) replaceGroup(w http.ResponseWriter, r *http.Request) {
+	in := &scimsdk.Group{}
+	if err := decode(r, in); err != nil {
+		writeError(w, err)
+		return
+	}
+	group, err := h.svc.ScimReplaceGroup(r.Context(), tenant(r), r.PathValue("id"), in)
+	h.writeGroup(w, r, http.StatusOK, group, err)
+}
+
+func (h *Handler) patchGroup(w http.ResponseWriter, r *http.Request) {
+	patch := &scimsdk.PatchOp{}
+	if err := decode(r, patch); err != nil {
+		writeError(w, err)
+		return
+	}
+	group, err := h.svc.ScimPatchGroup(r.Context(), tenant(r), r.PathValue("id"), patch)
+	h.writeGroup(w, r, http.StatusOK, group, err)
+}
+
+func (h *Handler) deleteGroup(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.ScimDeleteGroup(r.Context(), tenant(r), r.PathValue("id")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) writeUser(w http.ResponseWriter, r *http.Request, code int, user *scimsdk.User, err error) {
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	setUserLocation(r, user)
+	writeJSON(w, code, user)
+}
+
+func (h *Handler) writeGroup(w http.ResponseWriter, r *http.Request, code int, group *scimsdk.Group, err error) {
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	setGroupLocation(r, group)
+	writeJSON(w, code, group)
+}
+
+func tenant(r *http.Request) string {
+	tenantID, _ := r.Context().Value(tenantKey{}).(string)
+	return tenantID
+}
+
+// parsePage count没有传时返回-1，由service使用默认值
+func parsePage(r *http.Request) (startIndex, count int, err error) {
+	q := r.URL.Query()
+	startIndex, count = 1, -1
+	if v := q.Get("startIndex"); v != "" {
+		if startIndex, err = strconv.Atoi(v); err != nil {
+			return 0, 0, scimsdk.NewError(http.StatusBadRequest, scimsdk.ErrTypeInvalidValue, "invalid startIndex")
+		}
+	}
+	if v := q.Get("count"); v != "" {
+		if count, err = strconv.Atoi(v); err != nil || count < 0 {
+			return 0, 0, scimsdk.NewError(http.StatusBadRequest, scimsdk.ErrTypeInvalidValue, "invalid count")
+		}
+	}
+	return startIndex, count, nil
+}
+
+func decode(r *http.Request, v any) error {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return scimsdk.NewError(http.StatusBadRequest, scimsdk.ErrTypeInvalidSyntax, err.Error())
+	}
+	return nil
+}
+
+// location 反向代理后面部署时按X-Forwarded-Proto判断协议
+func location(r *http.Request, resource, id string) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host + BasePath + "/" + resource + "/" + id
+}
+
+func setUserLocation(r *http.Request, user *scimsdk.User) {
+	if user.Meta != nil {
+		user.Meta.Location = location(r, "Users", user.ID)
+	}
+}
+
+func setGroupLocation(r *http.Request, group *scimsdk.Group) {
+	if group.Meta != nil {
+		group.Meta.Location = location(r, "Groups", group.ID)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", scimsdk.ContentType)
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logx.Error("write scim response failed", zap.Error(err))
+	}
+}
+
+// writeError 业务错误按grpc状态码转换为http状态码
+func writeError(w http.ResponseWriter, err error) {
+	var scimErr *scimsdk.Error
+	if errors.As(err, &scimErr) {
+		writeJSON(w, scimErr.Code(), scimErr)
+		return
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		logx.Error("scim request failed", zap.Error(err))
+		writeJSON(w, http.StatusInternalServerError, scimsdk.NewError(http.StatusInternalServerError, "", "internal error"))
+		return
+	}
+	var e *scimsdk.Error
+	switch st.Code() {
+	case codes.InvalidArgument:
+		e = scimsdk.NewError(http.StatusBadRequest, scimsdk.ErrTypeInvalidValue, st.Message())
+	case codes.Unauthenticated:
+		e = scimsdk.NewError(http.StatusUnauthorized, "", st.Message())
+	case codes.PermissionDenied:
+		e = scimsdk.NewError(http.StatusForbidden, "", st.Message())
+	case codes.NotFound:
+		e = scimsdk.NewError(http.StatusNotFound, "", st.Message())
+	case codes.AlreadyExists:
+		e = scimsdk.NewError(http.StatusConflict, scimsdk.ErrTypeUniqueness, st.Message())
+	case codes.FailedPrecondition:
+		e = scimsdk.NewError(http.StatusBadRequest, scimsdk.ErrTypeMutability, st.Message())
+	default:
+		logx.Error("scim request failed", zap.Error(err))
+		e = scimsdk.NewError(http.StatusInternalServerError, "", "internal error")
+	}
+	writeJSON(w, e.Code(), e)
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+
+	"github.com/byteflowing/base/app/user/common"
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/pkg/db"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+func (u *UserService) PagingSearchUsers(ctx context.Context, req *userv1.PagingSearchUsersReq) (*userv1.PagingSearchUsersResp, error) {
+	q := u.db.UserAccount
+	tx := q.WithContext(ctx)
+	if req.Asc {
+		tx = tx.Order(q.ID.Asc())
+	} else {
+		tx = tx.Order(q.ID.Desc())
+	}
+	if req.TenantId != nil {
+		tx = tx.Where(q.TenantID.Eq(req.GetTenantId()))
+	}
+	if req.Status != nil {
+		tx = tx.Where(q.Status.Eq(int16(req.GetStatus())))
+	}
+	if req.Source != nil {
+		tx = tx.Where(q.Source.Eq(int16(req.GetSource())))
+	}
+	if region := req.Region; region != nil {
+		if region.CountryCode != "" {
+			tx = tx.Where(q.CountryCode.Eq(region.CountryCode))
+		}
+		if region.ProvinceCode != "" {
+			tx = tx.Where(q.ProvinceCode.Eq(region.ProvinceCode))
+		}
+		if region.CityCode != "" {
+			tx = tx.Where(q.CityCode.Eq(region.CityCode))
+		}
+		if region.DistrictCode != "" {
+			tx = tx.Where(q.DistrictCode.Eq(region.DistrictCode))
+		}
+	}
+	if req.CreatedStart != nil {
+		tx = tx.Where(q.CreatedAt.Gte(req.CreatedStart.AsTime()))
+	}
+	if req.CreatedEnd != nil {
+		tx = tx.Where(q.CreatedAt.Lte(req.CreatedEnd.AsTime()))
+	}
+	result, err := db.Paginate[model.UserAccount](tx.UnderlyingDB(), uint32(req.Page), uint32(req.Size))
+	if err != nil {
+		return nil, err
+	}
+	users := make([]*userv1.User, 0, len(result.List))
+	for _, item := range result.List {
+		users = append(users, common.UserModelToUser(item))
+	}
+	return &userv1.PagingSearchUsersResp{
+		Page:       int32(result.Page),
+		Size:       int32(result.PageSize),
+		Total:      int64(result.Total),
+		TotalPages: int32(result.TotalPages),
+		Users:      users,
+	}, nil
+}
+
+// DisableUser 禁用用户，同时吊销该用户所有的登录会话
+func (u *UserService) DisableUser(ctx context.Context, req *userv1.DisableUserReq) (*userv1.DisableUserResp, error) {
+	rv := &revocation{}
+	err := u.db.Transaction(func(tx *query.Query) error {
+		userAccount, err := u.getUserAccount(ctx, tx, req.Uid)
+		if err != nil {
+			return err
+		}
+		return u.disableUser(ctx, tx, rv, userAccount)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := u.addBlockItems(ctx, rv.items); err != nil {
+		return nil, err
+	}
+	return &userv1.DisableUserResp{}, nil
+}
+
+// disableUser 禁用账号并吊销所有会话
+func (u *UserService) disableUser(ctx context.Context, tx *query.Query, rv *revocation, userAccount *model.UserAccount) error {
+	q := tx.UserAccount
+	if _, err := q.WithContext(ctx).Where(q.ID.Eq(userAccount.ID)).Update(q.Status, int16(enumsv1.UserStatus_USER_STATUS_DISABLED)); err != nil {
+		return err
+	}
+	if err := u.revokeSessions(ctx, tx, rv, userAccount.ID, enumsv1.SignInStatus_SIGN_IN_STATUS_REVOKED); err != nil {
+		return err
+	}
+	return u.emitEvent(ctx, tx, enumsv1.UserEventType_USER_EVENT_TYPE_DISABLED, userAccount.TenantID, userAccount.ID, &userv1.UserDisabledEvent{})
+}
+
+// EnableUser 只能启用被禁用的账号，注销中或已注销的账号不能通过启用恢复
+func (u *UserService) EnableUser(ctx context.Context, req *userv1.EnableUserReq) (*userv1.EnableUserResp, error) {
+	userAccount, err := u.getUserAccount(ctx, u.db, req.Uid)
+	if err != nil {
+		return nil, err
+	}
+	if userAccount.Status == int16(enumsv1.UserStatus_USER_STATUS_OK) {
+		return &userv1.EnableUserResp{}, nil
+	}
+	q := u.db.UserAccount
+	info, err := q.WithContext(ctx).Where(
+		q.ID.Eq(req.Uid),
+		q.Status.Eq(int16(enumsv1.UserStatus_USER_STATUS_DISABLED)),
+	).Update(q.Status, int16(enumsv1.UserStatus_USER_STATUS_OK))
+	if err != nil {
+		return nil, err
+	}
+	if info.RowsAffected == 0 {
+		return nil, ecode.ErrUserNotDisabled
+	}
+	return &userv1.EnableUserResp{}, nil
+}
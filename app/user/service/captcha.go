@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+
+	"github.com/byteflowing/base/ecode"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	msgv1 "github.com/byteflowing/proto/gen/go/msg/v1"
+	typesv1 "github.com/byteflowing/proto/gen/go/types/v1"
+)
+
+// verifyPhoneCaptcha 校验客户端通过MessageService.SendCaptcha获取的短信验证码
+func (u *UserService) verifyPhoneCaptcha(
+	ctx context.Context,
+	phone *typesv1.PhoneNumber,
+	token, code string,
+	scene enumsv1.MessageSceneType,
+) error {
+	if phone == nil || phone.Number == "" {
+		return ecode.ErrParams
+	}
+	return u.verifyCaptcha(ctx, &msgv1.VerifyCaptchaReq{
+		MessageSenderType: enumsv1.MessageSenderType_MESSAGE_SENDER_TYPE_SMS,
+		SceneType:         scene,
+		Token:             token,
+		Captcha:           code,
+		Target:            &msgv1.VerifyCaptchaReq_Phone{Phone: phone},
+	})
+}
+
+// verifyMailCaptcha 校验客户端通过MessageService.SendCaptcha获取的邮件验证码
+func (u *UserService) verifyMailCaptcha(ctx context.Context, email, token, code string, scene enumsv1.MessageSceneType) error {
+	if email == "" {
+		return ecode.ErrParams
+	}
+	return u.verifyCaptcha(ctx, &msgv1.VerifyCaptchaReq{
+		MessageSenderType: enumsv1.MessageSenderType_MESSAGE_SENDER_TYPE_MAIL,
+		SceneType:         scene,
+		Token:             token,
+		Captcha:           code,
+		Target:            &msgv1.VerifyCaptchaReq_Mail{Mail: email},
+	})
+}
+
+func (u *UserService) verifyCaptcha(ctx context.Context, req *msgv1.VerifyCaptchaReq) error {
+	if u.msg == nil {
+		return ecode.ErrUnImplemented
+	}
+	if req.Captcha == "" {
+		return ecode.ErrUserCaptchaInvalid
+	}
+	resp, err := u.msg.VerifyCaptcha(ctx, req)
+	if err != nil {
+		return err
+	}
+	if resp.Result != nil {
+		return ecode.ErrUserCaptchaInvalid
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/byteflowing/base/app/user/common"
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/ecode"
+	"github.com/byteflowing/base/pkg/utils/crypto"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	typesv1 "github.com/byteflowing/proto/gen/go/types/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+const (
+	contactProofTriesKeyFormat = "%s:contact_proof_tries:%d" // prefix:uid
+
+	// 未配置mfa时密码验证使用的次数限制
+	defaultContactProofMaxTries = 5
+	defaultContactProofTriesTtl = 15 * time.Minute
+)
+
+// ChangePhone 更换手机号，新手机号需要通过验证码验证
+// 原手机号已验证时还需要验证原手机号，原手机号无法接收验证码时可以使用密码或两步验证代替
+func (u *UserService) ChangePhone(ctx context.Context, req *userv1.ChangePhoneReq) (*userv1.ChangePhoneResp, error) {
+	number := req.NewPhoneNumber
+	if number == nil || number.Number == "" {
+		return nil, ecode.ErrParams
+	}
+	uid, _, err := u.parseSensitiveAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	userAccount, err := u.getUserAccount(ctx, u.db, uid)
+	if err != nil {
+		return nil, err
+	}
+	if !common.IsUserValid(userAccount.Status) {
+		return nil, ecode.ErrUserDisabled
+	}
+	if userAccount.PhoneVerified && userAccount.PhoneCountryCode == number.CountryCode && userAccount.Phone == number.Number {
+		return nil, ecode.ErrParams
+	}
+	scene := enumsv1.MessageSceneType_MESSAGE_SCENE_TYPE_CHANGE_PHONE
+	var proofType enumsv1.ContactProofType
+	if userAccount.PhoneVerified {
+		oldNumber := &typesv1.PhoneNumber{CountryCode: userAccount.PhoneCountryCode, Number: userAccount.Phone}
+		proofType, err = u.verifyContactProof(ctx, userAccount, req.OldProof, func(c *userv1.CaptchaProof) error {
+			return u.verifyPhoneCaptcha(ctx, oldNumber, c.Token, c.Captcha, scene)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := u.verifyPhoneCaptcha(ctx, number, req.CaptchaToken, req.Captcha, scene); err != nil {
+		return nil, err
+	}
+	var user *userv1.User
+	err = u.db.Transaction(func(tx *query.Query) error {
+		accountQ := tx.UserAccount
+		count, err := accountQ.WithContext(ctx).Where(
+			accountQ.TenantID.Eq(userAccount.TenantID),
+			accountQ.PhoneCountryCode.Eq(number.CountryCode),
+			accountQ.Phone.Eq(number.Number),
+			accountQ.ID.Neq(uid),
+		).Count()
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ecode.ErrUserPhoneExists
+		}
+		if _, err := accountQ.WithContext(ctx).Where(accountQ.ID.Eq(uid)).UpdateSimple(
+			accountQ.PhoneCountryCode.Value(number.CountryCode),
+			accountQ.Phone.Value(number.Number),
+			accountQ.PhoneVerified.Value(true),
+		); err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ecode.ErrUserPhoneExists
+			}
+			return err
+		}
+		event := &userv1.UserContactChangedEvent{
+			Channel:   enumsv1.ContactChannel_CONTACT_CHANNEL_PHONE,
+			ProofType: proofType,
+			New:       number.CountryCode + number.Number,
+		}
+		// 占位手机号不是真实的手机号，不记录
+		if userAccount.PhoneCountryCode != "" {
+			event.Old = userAccount.PhoneCountryCode + userAccount.Phone
+		}
+		user, err = u.afterContactChanged(ctx, tx, uid, event)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.ChangePhoneResp{User: user}, nil
+}
+
+// ChangeEmail 更换邮箱，验证规则和ChangePhone一致
+func (u *UserService) ChangeEmail(ctx context.Context, req *userv1.ChangeEmailReq) (*userv1.ChangeEmailResp, error) {
+	email := common.NormalizeEmail(req.NewEmail)
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email || common.IsPlaceholderEmail(email) {
+		return nil, ecode.ErrParams
+	}
+	uid, _, err := u.parseSensitiveAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	userAccount, err := u.getUserAccount(ctx, u.db, uid)
+	if err != nil {
+		return nil, err
+	}
+	if !common.IsUserValid(userAccount.Status) {
+		return nil, ecode.ErrUserDisabled
+	}
+	if userAccount.EmailVerified && userAccount.Email == email {
+		return nil, ecode.ErrParams
+	}
+	scene := enumsv1.MessageSceneType_MESSAGE_SCENE_TYPE_CHANGE_EMAIL
+	var proofType enumsv1.ContactProofType
+	if userAccount.EmailVerified {
+		proofType, err = u.verifyContactProof(ctx, userAccount, req.OldProof, func(c *userv1.CaptchaProof) error {
+			return u.verifyMailCaptcha(ctx, userAccount.Email, c.Token, c.Captcha, scene)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := u.verifyMailCaptcha(ctx, email, req.CaptchaToken, req.Captcha, scene); err != nil {
+		return nil, err
+	}
+	var user *userv1.User
+	err = u.db.Transaction(func(tx *query.Query) error {
+		accountQ := tx.UserAccount
+		count, err := accountQ.WithContext(ctx).Where(
+			accountQ.TenantID.Eq(userAccount.TenantID),
+			accountQ.Email.Eq(email),
+			accountQ.ID.Neq(uid),
+		).Count()
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ecode.ErrUserEmailExists
+		}
+		if _, err := accountQ.WithContext(ctx).Where(accountQ.ID.Eq(uid)).UpdateSimple(
+			accountQ.Email.Value(email),
+			accountQ.EmailVerified.Value(true),
+		); err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ecode.ErrUserEmailExists
+			}
+			return err
+		}
+		event := &userv1.UserContactChangedEvent{
+			Channel:   enumsv1.ContactChannel_CONTACT_CHANNEL_EMAIL,
+			ProofType: proofType,
+			New:       email,
+		}
+		if !common.IsPlaceholderEmail(userAccount.Email) {
+			event.Old = userAccount.Email
+		}
+		user, err = u.afterContactChanged(ctx, tx, uid, event)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.ChangeEmailResp{User: user}, nil
+}
+
+// verifyContactProof 验证用户对原手机号或邮箱的所有权，返回使用的验证方式
+// 密码和两步验证的尝试次数按用户限制，验证码由消息服务限制
+func (u *UserService) verifyContactProof(
+	ctx context.Context,
+	userAccount *model.UserAccount,
+	proof *userv1.ContactProof,
+	verifyOld func(c *userv1.CaptchaProof) error,
+) (enumsv1.ContactProofType, error) {
+	if proof.GetCaptcha() != nil {
+		if err := verifyOld(proof.GetCaptcha()); err != nil {
+			return 0, err
+		}
+		return enumsv1.ContactProofType_CONTACT_PROOF_TYPE_CAPTCHA, nil
+	}
+	key := fmt.Sprintf(contactProofTriesKeyFormat, u.cfg.KeyPrefix, userAccount.ID)
+	maxTries, ttl := int64(defaultContactProofMaxTries), defaultContactProofTriesTtl
+	if cfg := u.cfg.Mfa; cfg != nil && cfg.MaxTries > 0 && cfg.PendingTtl.AsDuration() > 0 {
+		maxTries, ttl = int64(cfg.MaxTries), cfg.PendingTtl.AsDuration()
+	}
+	tries, err := u.incrTries(ctx, key, ttl)
+	if err != nil {
+		return 0, err
+	}
+	if tries > maxTries {
+		return 0, ecode.ErrTooManyRequests
+	}
+	proofType, err := u.verifyContactSecret(ctx, userAccount, proof)
+	if err != nil {
+		return 0, err
+	}
+	if err := u.rdb.Del(ctx, key).Err(); err != nil {
+		return 0, err
+	}
+	return proofType, nil
+}
+
+// verifyContactSecret 使用密码或两步验证代替原手机号或邮箱的验证码
+func (u *UserService) verifyContactSecret(ctx context.Context, userAccount *model.UserAccount, proof *userv1.ContactProof) (enumsv1.ContactProofType, error) {
+	switch {
+	case proof.GetPassword() != "":
+		if userAccount.Password == nil {
+			return 0, ecode.ErrUserPasswordInvalid
+		}
+		ok, err := crypto.DefaultPasswordHasher.VerifyPassword(proof.GetPassword(), *userAccount.Password)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return 0, ecode.ErrUserPasswordInvalid
+		}
+		return enumsv1.ContactProofType_CONTACT_PROOF_TYPE_PASSWORD, nil
+	case proof.GetMfa() != nil:
+		if u.totp == nil {
+			return 0, ecode.ErrUserMfaUnsupported
+		}
+		mfa := proof.GetMfa()
+		if err := u.verifyMfaCode(ctx, u.db, userAccount.ID, mfa.Code, mfa.RecoveryCode); err != nil {
+			return 0, err
+		}
+		return enumsv1.ContactProofType_CONTACT_PROOF_TYPE_MFA, nil
+	default:
+		return 0, ecode.ErrParams
+	}
+}
+
+// afterContactChanged 发布审计事件并返回更新后的用户信息
+func (u *UserService) afterContactChanged(ctx context.Context, tx *query.Query, uid int64, event *userv1.UserContactChangedEvent) (*userv1.User, error) {
+	userAccount, err := u.getUserAccount(ctx, tx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.emitEvent(ctx, tx, enumsv1.UserEventType_USER_EVENT_TYPE_CONTACT_CHANGED, userAccount.TenantID, uid, event); err != nil {
+		return nil, err
+	}
+	return common.UserModelToUser(userAccount), nil
+}
@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+
+	"github.com/byteflowing/base/app/user/common"
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/ecode"
+	"github.com/byteflowing/base/pkg/jsonx"
+	"github.com/byteflowing/base/pkg/logx"
+	"github.com/byteflowing/base/pkg/utils/trans"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+const (
+	taskDeleteAccount = "user:delete_account"
+
+	deleteAccountTaskIDFormat = "user:delete_account:%d" // deletionID
+	anonymizedEmailFormat     = "%d@deleted.invalid"     // uid
+)
+
+// DeleteAccount 申请注销账号
+// 注销会在冷静期结束后由asynq任务执行，冷静期内可以调用CancelDeleteAccount撤销
+func (u *UserService) DeleteAccount(ctx context.Context, req *userv1.DeleteAccountReq) (*userv1.DeleteAccountResp, error) {
+	if u.cfg.Deletion == nil {
+		return nil, ecode.ErrUnImplemented
+	}
+	uid, _, err := u.parseSensitiveAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	scheduledAt := time.Now().Add(u.cfg.Deletion.CoolingOff.AsDuration())
+	var deletion *model.UserDeletion
+	err = u.db.Transaction(func(tx *query.Query) error {
+		userAccount, err := u.getUserAccount(ctx, tx, uid)
+		if err != nil {
+			return err
+		}
+		q := tx.UserDeletion
+		_, err = q.WithContext(ctx).Where(
+			q.UID.Eq(uid),
+			q.Status.Eq(int16(enumsv1.DeletionStatus_DELETION_STATUS_PENDING)),
+		).Take()
+		if err == nil {
+			return ecode.ErrUserDeletionPending
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		deletion = &model.UserDeletion{
+			TenantID:    userAccount.TenantID,
+			UID:         uid,
+			Status:      int16(enumsv1.DeletionStatus_DELETION_STATUS_PENDING),
+			ScheduledAt: &scheduledAt,
+		}
+		return q.WithContext(ctx).Create(deletion)
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 事务提交后再入队，避免事务回滚后留下没有注销申请的任务
+	// 入队失败时删除注销申请，用户可以重新申请
+	if err := u.queue.EnQueue(
+		ctx,
+		taskDeleteAccount,
+		&userv1.DeleteAccountTask{DeletionId: deletion.ID, Uid: uid},
+		asynq.ProcessAt(scheduledAt),
+		asynq.TaskID(fmt.Sprintf(deleteAccountTaskIDFormat, deletion.ID)),
+		asynq.MaxRetry(int(u.cfg.Deletion.MaxRetry)),
+	); err != nil {
+		q := u.db.UserDeletion
+		if _, delErr := q.WithContext(ctx).Unscoped().Where(q.ID.Eq(deletion.ID)).Delete(); delErr != nil {
+			logx.CtxError(ctx, "failed to remove deletion after enqueue failure", zap.Int64("deletionID", deletion.ID), zap.Error(delErr))
+		}
+		return nil, err
+	}
+	return &userv1.DeleteAccountResp{ScheduledAt: timestamppb.New(scheduledAt)}, nil
+}
+
+// CancelDeleteAccount 冷静期内撤销注销申请
+// 已入队的asynq任务不会被删除，执行时检查到申请状态不是pending会直接跳过
+func (u *UserService) CancelDeleteAccount(ctx context.Context, req *userv1.CancelDeleteAccountReq) (*userv1.CancelDeleteAccountResp, error) {
+	uid, _, err := u.parseSensitiveAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	q := u.db.UserDeletion
+	info, err := q.WithContext(ctx).Where(
+		q.UID.Eq(uid),
+		q.Status.Eq(int16(enumsv1.DeletionStatus_DELETION_STATUS_PENDING)),
+	).Update(q.Status, int16(enumsv1.DeletionStatus_DELETION_STATUS_CANCELLED))
+	if err != nil {
+		return nil, err
+	}
+	if info.RowsAffected == 0 {
+		return nil, ecode.ErrUserDeletionNotFound
+	}
+	return &userv1.CancelDeleteAccountResp{}, nil
+}
+
+// ExportMyData 导出用户的个人数据，包括账号信息、绑定的第三方身份以及登录记录（包括已归档的登录记录）
+func (u *UserService) ExportMyData(ctx context.Context, req *userv1.ExportMyDataReq) (*userv1.ExportMyDataResp, error) {
+	uid, _, err := u.parseSensitiveAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	userAccount, err := u.getUserAccount(ctx, u.db, uid)
+	if err != nil {
+		return nil, err
+	}
+	account, err := protojson.Marshal(common.UserModelToUser(userAccount))
+	if err != nil {
+		return nil, err
+	}
+	authQ := u.db.UserAuth
+	auths, err := authQ.WithContext(ctx).Where(authQ.UID.Eq(uid)).Find()
+	if err != nil {
+		return nil, err
+	}
+	logQ := u.db.UserSignLog
+	logs, err := logQ.WithContext(ctx).Where(logQ.UID.Eq(uid)).Order(logQ.CreatedAt.Desc()).Find()
+	if err != nil {
+		return nil, err
+	}
+	// 归档的登录日志都早于user_sign_log中的日志，追加在后面
+	archiveQ := u.db.UserSignLogArchive
+	archivedLogs, err := archiveQ.WithContext(ctx).Where(archiveQ.UID.Eq(uid)).Order(archiveQ.CreatedAt.Desc()).Find()
+	if err != nil {
+		return nil, err
+	}
+	archive := &exportArchive{
+		ExportedAt:    time.Now(),
+		Account:       account,
+		Identities:    make([]*exportIdentity, 0, len(auths)),
+		SignInHistory: make([]*exportSignLog, 0, len(logs)+len(archivedLogs)),
+	}
+	for _, a := range auths {
+		archive.Identities = append(archive.Identities, &exportIdentity{
+			Type:      enumsv1.SignInType(a.Type).String(),
+			Appid:     a.Appid,
+			OpenID:    a.OpenID,
+			UnionID:   a.UnionID,
+			CreatedAt: a.CreatedAt,
+		})
+	}
+	for _, l := range logs {
+		archive.SignInHistory = append(archive.SignInHistory, &exportSignLog{
+			Type:      enumsv1.SignInType(l.Type).String(),
+			Status:    enumsv1.SignInStatus(l.Status).String(),
+			IP:        trans.Deref(l.IP),
+			Location:  trans.Deref(l.Location),
+			Agent:     trans.Deref(l.Agent),
+			Device:    trans.Deref(l.Device),
+			CreatedAt: l.CreatedAt,
+		})
+	}
+	for _, l := range archivedLogs {
+		archive.SignInHistory = append(archive.SignInHistory, &exportSignLog{
+			Type:      enumsv1.SignInType(l.Type).String(),
+			Status:    enumsv1.SignInStatus(l.Status).String(),
+			IP:        trans.Deref(l.IP),
+			Location:  trans.Deref(l.Location),
+			Agent:     trans.Deref(l.Agent),
+			Device:    trans.Deref(l.Device),
+			CreatedAt: l.CreatedAt,
+		})
+	}
+	data, err := jsonx.Marshal(archive)
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.ExportMyDataResp{
+		FileName: fmt.Sprintf("user_%s_%s.json", userAccount.Number, time.Now().Format("20060102150405")),
+		Data:     data,
+	}, nil
+}
+
+type exportArchive struct {
+	ExportedAt    time.Time         `json:"exported_at"`
+	Account       json.RawMessage   `json:"account"`
+	Identities    []*exportIdentity `json:"identities"`
+	SignInHistory []*exportSignLog  `json:"sign_in_history"`
+}
+
+type exportIdentity struct {
+	Type      string     `json:"type"`
+	Appid     string     `json:"appid"`
+	OpenID    string     `json:"open_id"`
+	UnionID   string     `json:"union_id"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+type exportSignLog struct {
+	Type      string     `json:"type"`
+	Status    string     `json:"status"`
+	IP        string     `json:"ip"`
+	Location  string     `json:"location"`
+	Agent     string     `json:"agent"`
+	Device    string     `json:"device"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+// asynq的回调函数
+// 冷静期结束后执行注销：匿名化账号中的个人信息，删除第三方身份和登录设备，清理登录日志（包括归档）中的设备及位置信息
+func (u *UserService) deleteAccount(ctx context.Context, task *asynq.Task) error {
+	payload := &userv1.DeleteAccountTask{}
+	if err := proto.Unmarshal(task.Payload(), payload); err != nil {
+		return err
+	}
+	rv := &revocation{}
+	err := u.db.Transaction(func(tx *query.Query) error {
+		q := tx.UserDeletion
+		deletion, err := q.WithContext(ctx).Where(q.ID.Eq(payload.DeletionId)).Take()
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		if deletion.Status != int16(enumsv1.DeletionStatus_DELETION_STATUS_PENDING) {
+			logx.CtxInfo(ctx, "skip account deletion", zap.Int64("deletionID", deletion.ID), zap.Int16("status", deletion.Status))
+			return nil
+		}
+		if err := u.anonymizeAccount(ctx, tx, rv, deletion.UID); err != nil {
+			return err
+		}
+		_, err = q.WithContext(ctx).Where(q.ID.Eq(deletion.ID)).Updates(&model.UserDeletion{
+			Status:     int16(enumsv1.DeletionStatus_DELETION_STATUS_DONE),
+			FinishedAt: trans.Ref(time.Now()),
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return u.addBlockItems(ctx, rv.items)
+}
+
+func (u *UserService) anonymizeAccount(ctx context.Context, tx *query.Query, rv *revocation, uid int64) error {
+	if err := u.revokeSessions(ctx, tx, rv, uid, enumsv1.SignInStatus_SIGN_IN_STATUS_REVOKED); err != nil {
+		return err
+	}
+	// 手机号和邮箱上有唯一索引，使用uid生成占位值避免冲突
+	accountQ := tx.UserAccount
+	if _, err := accountQ.WithContext(ctx).Where(accountQ.ID.Eq(uid)).UpdateSimple(
+		accountQ.Name.Null(),
+		accountQ.Alias_.Null(),
+		accountQ.Password.Null(),
+		accountQ.Avatar.Null(),
+		accountQ.Gender.Null(),
+		accountQ.Birthday.Null(),
+		accountQ.PhoneCountryCode.Value(""),
+		accountQ.Phone.Value(common.PlaceholderPhone(uid)),
+		accountQ.Email.Value(fmt.Sprintf(anonymizedEmailFormat, uid)),
+		accountQ.PhoneVerified.Value(false),
+		accountQ.EmailVerified.Value(false),
+		accountQ.CountryCode.Value(""),
+		accountQ.ProvinceCode.Value(""),
+		accountQ.CityCode.Value(""),
+		accountQ.DistrictCode.Value(""),
+		accountQ.Addr.Null(),
+		accountQ.RegisterIP.Null(),
+		accountQ.RegisterDevice.Null(),
+		accountQ.RegisterAgent.Null(),
+		accountQ.RegisterLocation.Null(),
+		accountQ.Ext.Null(),
+		accountQ.Status.Value(int16(enumsv1.UserStatus_USER_STATUS_DELETED)),
+	); err != nil {
+		return err
+	}
+	if _, err := accountQ.WithContext(ctx).Where(accountQ.ID.Eq(uid)).Delete(); err != nil {
+		return err
+	}
+	authQ := tx.UserAuth
+	if _, err := authQ.WithContext(ctx).Unscoped().Where(authQ.UID.Eq(uid)).Delete(); err != nil {
+		return err
+	}
+	totpQ := tx.UserTotp
+	if _, err := totpQ.WithContext(ctx).Unscoped().Where(totpQ.UID.Eq(uid)).Delete(); err != nil {
+		return err
+	}
+	codeQ := tx.UserRecoveryCode
+	if _, err := codeQ.WithContext(ctx).Unscoped().Where(codeQ.UID.Eq(uid)).Delete(); err != nil {
+		return err
+	}
+	historyQ := tx.UserPasswordHistory
+	if _, err := historyQ.WithContext(ctx).Unscoped().Where(historyQ.UID.Eq(uid)).Delete(); err != nil {
+		return err
+	}
+	scimQ := tx.UserScimUser
+	if _, err := scimQ.WithContext(ctx).Unscoped().Where(scimQ.UID.Eq(uid)).Delete(); err != nil {
+		return err
+	}
+	deviceQ := tx.UserDevice
+	if _, err := deviceQ.WithContext(ctx).Unscoped().Where(deviceQ.UID.Eq(uid)).Delete(); err != nil {
+		return err
+	}
+	logQ := tx.UserSignLog
+	if _, err := logQ.WithContext(ctx).Unscoped().Where(logQ.UID.Eq(uid)).UpdateSimple(
+		logQ.Identifier.Value(""),
+		logQ.IP.Null(),
+		logQ.Location.Null(),
+		logQ.Agent.Null(),
+		logQ.Device.Null(),
+	); err != nil {
+		return err
+	}
+	archiveQ := tx.UserSignLogArchive
+	_, err := archiveQ.WithContext(ctx).Unscoped().Where(archiveQ.UID.Eq(uid)).UpdateSimple(
+		archiveQ.Identifier.Value(""),
+		archiveQ.IP.Null(),
+		archiveQ.Location.Null(),
+		archiveQ.Agent.Null(),
+		archiveQ.Device.Null(),
+	)
+	return err
+}
@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+
+	"github.com/byteflowing/base/app/user/common"
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/ecode"
+	"github.com/byteflowing/base/pkg/logx"
+	"github.com/byteflowing/base/pkg/mail"
+	"github.com/byteflowing/base/pkg/utils/crypto"
+	"github.com/byteflowing/base/pkg/utils/trans"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	msgv1 "github.com/byteflowing/proto/gen/go/msg/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+const (
+	defaultRevokeLinkTtl = 7 * 24 * time.Hour
+	revokeLinkTokenParam = "token"
+
+	// 提醒模板中可以使用的参数
+	alertParamDevice    = "device"
+	alertParamIP        = "ip"
+	alertParamLocation  = "location"
+	alertParamTime      = "time"
+	alertParamRevokeURL = "revoke_url"
+	alertTimeLayout     = "2006-01-02 15:04:05"
+)
+
+// deviceAlert 登录后需要发送的提醒
+type deviceAlert struct {
+	reason enumsv1.DeviceAlertReason
+	device string
+	log    *model.UserSignLog
+}
+
+func (u *UserService) ListMyDevices(ctx context.Context, req *userv1.ListMyDevicesReq) (*userv1.ListMyDevicesResp, error) {
+	uid, _, err := u.parseAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	q := u.db.UserDevice
+	models, err := q.WithContext(ctx).Where(q.UID.Eq(uid)).Order(q.LastSeenAt.Desc()).Find()
+	if err != nil {
+		return nil, err
+	}
+	devices := make([]*userv1.Device, 0, len(models))
+	for _, m := range models {
+		devices = append(devices, deviceModelToDevice(m))
+	}
+	return &userv1.ListMyDevicesResp{Devices: devices}, nil
+}
+
+// RemoveMyDevice 删除设备并吊销该设备上所有未过期的登录，设备再次登录时会被当作新设备
+func (u *UserService) RemoveMyDevice(ctx context.Context, req *userv1.RemoveMyDeviceReq) (*userv1.RemoveMyDeviceResp, error) {
+	uid, _, err := u.parseAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	rv := &revocation{}
+	err = u.db.Transaction(func(tx *query.Query) error {
+		q := tx.UserDevice
+		device, err := q.WithContext(ctx).Where(q.ID.Eq(req.Id), q.UID.Eq(uid)).Take()
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ecode.ErrUserDeviceNotFound
+			}
+			return err
+		}
+		if _, err := q.WithContext(ctx).Unscoped().Where(q.ID.Eq(device.ID)).Delete(); err != nil {
+			return err
+		}
+		return u.revokeDeviceSessions(ctx, tx, rv, uid, device.Device)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := u.addBlockItems(ctx, rv.items); err != nil {
+		return nil, err
+	}
+	return &userv1.RemoveMyDeviceResp{}, nil
+}
+
+// RevokeSignIn 用户点击提醒中的"不是我本人登录"链接，吊销对应的登录并取消设备的信任
+// 链接中的token只能使用一次
+func (u *UserService) RevokeSignIn(ctx context.Context, req *userv1.RevokeSignInReq) (*userv1.RevokeSignInResp, error) {
+	claims, err := u.token.Parse(req.Token, enumsv1.TokenType_TOKEN_TYPE_SIGN_IN_REVOKE.String())
+	if err != nil {
+		return nil, err
+	}
+	blocked, err := u.isJtiBlocked(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, ecode.ErrUserTokenInvalid
+	}
+	uid, err := common.GetJwtUID(claims)
+	if err != nil {
+		return nil, ecode.ErrUserTokenInvalid
+	}
+	logID, err := strconv.ParseInt(common.GetTokenSignLogID(claims), 10, 64)
+	if err != nil {
+		return nil, ecode.ErrUserTokenInvalid
+	}
+	var logModel *model.UserSignLog
+	err = u.db.Transaction(func(tx *query.Query) error {
+		logQ := tx.UserSignLog
+		logModel, err = logQ.WithContext(ctx).Where(logQ.ID.Eq(logID), logQ.UID.Eq(uid)).Take()
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ecode.ErrUserTokenInvalid
+			}
+			return err
+		}
+		if _, err := logQ.WithContext(ctx).Where(logQ.ID.Eq(logID)).Update(logQ.Status, int16(enumsv1.SignInStatus_SIGN_IN_STATUS_REVOKED)); err != nil {
+			return err
+		}
+		if logModel.Device == nil || *logModel.Device == "" {
+			return nil
+		}
+		q := tx.UserDevice
+		_, err = q.WithContext(ctx).Where(
+			q.UID.Eq(uid),
+			q.DeviceHash.Eq(crypto.Sha256Hex([]byte(*logModel.Device))),
+		).UpdateSimple(q.Trusted.Value(false), q.TrustedAt.Null())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := u.addJtiToBlkByLog(ctx, logModel); err != nil {
+		return nil, err
+	}
+	if err := u.addJtiToBlkByClaims(ctx, claims); err != nil {
+		return nil, err
+	}
+	return &userv1.RevokeSignInResp{}, nil
+}
+
+// trackDevice 记录登录设备，通过mfa的登录会把设备标记为受信任
+// 返回需要发送的提醒：已有其他设备的用户在新设备登录，或者未受信任的设备在远离以往登录地点的位置登录
+func (u *UserService) trackDevice(
+	ctx context.Context,
+	tx *query.Query,
+	logModel *model.UserSignLog,
+	mfaPassed bool,
+) (*deviceAlert, error) {
+	if logModel.Device == nil || *logModel.Device == "" {
+		return nil, nil
+	}
+	now := time.Now()
+	name := *logModel.Device
+	hash := crypto.Sha256Hex([]byte(name))
+	q := tx.UserDevice
+	devices, err := q.WithContext(ctx).Where(q.UID.Eq(logModel.UID)).Find()
+	if err != nil {
+		return nil, err
+	}
+	var current *model.UserDevice
+	var others []*model.UserDevice
+	for _, d := range devices {
+		if d.DeviceHash == hash {
+			current = d
+		} else {
+			others = append(others, d)
+		}
+	}
+	var alert *deviceAlert
+	if current == nil {
+		current = &model.UserDevice{
+			TenantID:     logModel.TenantID,
+			UID:          logModel.UID,
+			DeviceHash:   hash,
+			Device:       name,
+			Trusted:      mfaPassed,
+			LastIP:       logModel.IP,
+			LastLocation: logModel.Location,
+			LastSeenAt:   &now,
+		}
+		if mfaPassed {
+			current.TrustedAt = &now
+		}
+		if err := q.WithContext(ctx).Create(current); err != nil {
+			return nil, err
+		}
+		if len(others) > 0 {
+			alert = &deviceAlert{reason: enumsv1.DeviceAlertReason_DEVICE_ALERT_REASON_NEW_DEVICE}
+		}
+	} else {
+		if !current.Trusted && u.isFarAway(logModel.Location, devices) {
+			alert = &deviceAlert{reason: enumsv1.DeviceAlertReason_DEVICE_ALERT_REASON_FAR_LOCATION}
+		}
+		updates := &model.UserDevice{LastSeenAt: &now, LastIP: logModel.IP, LastLocation: logModel.Location}
+		if mfaPassed && !current.Trusted {
+			updates.Trusted = true
+			updates.TrustedAt = &now
+		}
+		if _, err := q.WithContext(ctx).Where(q.ID.Eq(current.ID)).Updates(updates); err != nil {
+			return nil, err
+		}
+	}
+	if alert != nil {
+		alert.device = name
+		alert.log = logModel
+	}
+	return alert, nil
+}
+
+// isFarAway 登录位置和用户所有设备最近一次登录的位置都超过配置的距离
+func (u *UserService) isFarAway(location *string, devices []*model.UserDevice) bool {
+	cfg := u.cfg.DeviceAlert
+	if cfg == nil || cfg.DistanceKm <= 0 || location == nil {
+		return false
+	}
+	current := common.ParseLocationFromString(location)
+	known := 0
+	for _, d := range devices {
+		if d.LastLocation == nil {
+			continue
+		}
+		known++
+		if common.DistanceKm(current, common.ParseLocationFromString(d.LastLocation)) <= cfg.DistanceKm {
+			return false
+		}
+	}
+	return known > 0
+}
+
+// sendDeviceAlert 通过MessageService发送登录提醒，优先使用已验证的邮箱，其次使用已验证的手机号
+// 提醒发送失败只记录日志，不影响登录
+func (u *UserService) sendDeviceAlert(ctx context.Context, user *userv1.User, alert *deviceAlert) {
+	cfg := u.cfg.DeviceAlert
+	if alert == nil || cfg == nil || u.msg == nil {
+		return
+	}
+	revokeURL, err := u.revokeLink(alert.log)
+	if err != nil {
+		logx.CtxError(ctx, "generate revoke link failed", zap.Int64("uid", alert.log.UID), zap.Error(err))
+		return
+	}
+	params := map[string]string{
+		alertParamDevice:    alert.device,
+		alertParamIP:        trans.Deref(alert.log.IP),
+		alertParamLocation:  trans.Deref(alert.log.Location),
+		alertParamTime:      time.Now().Format(alertTimeLayout),
+		alertParamRevokeURL: revokeURL,
+	}
+	switch {
+	case cfg.Mail != nil && user.GetEmail() != "" && user.GetEmailVerify():
+		err = u.sendAlertMail(ctx, cfg.Mail, user.GetEmail(), params)
+	case cfg.Sms != nil && user.GetPhoneNumber() != nil && user.GetPhoneVerify():
+		_, err = u.msg.SendSms(ctx, &msgv1.SendSmsReq{
+			Vendor:         cfg.Sms.Vendor,
+			Account:        cfg.Sms.Account,
+			PhoneNumber:    user.GetPhoneNumber(),
+			SignName:       cfg.Sms.SignName,
+			TemplateCode:   cfg.Sms.TemplateCode,
+			TemplateParams: params,
+		})
+	default:
+		return
+	}
+	if err != nil {
+		logx.CtxWarn(ctx, "send device alert failed",
+			zap.Int64("uid", alert.log.UID),
+			zap.String("reason", alert.reason.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+func (u *UserService) sendAlertMail(ctx context.Context, cfg *userv1.DeviceAlertMail, to string, params map[string]string) error {
+	engine := mail.GoTemplateEngine{EnableHTML: cfg.ContentType == enumsv1.MailContentType_MAIL_CONTENT_TYPE_HTML}
+	content, err := engine.Render(cfg.Content, params)
+	if err != nil {
+		return err
+	}
+	_, err = u.msg.SendMail(ctx, &msgv1.SendMailReq{
+		Vendor:      cfg.Vendor,
+		Account:     cfg.Account,
+		From:        cfg.From,
+		To:          []*msgv1.MailAddress{{Address: to}},
+		Subject:     cfg.Subject,
+		ContentType: cfg.ContentType,
+		Content:     content,
+	})
+	return err
+}
+
+// revokeLink 生成"不是我本人登录"的链接，token中记录登录日志id
+func (u *UserService) revokeLink(logModel *model.UserSignLog) (string, error) {
+	cfg := u.cfg.DeviceAlert
+	ttl := cfg.RevokeTtl.AsDuration()
+	if ttl <= 0 {
+		ttl = defaultRevokeLinkTtl
+	}
+	token, err := u.token.Generate(
+		strconv.FormatInt(logModel.UID, 10),
+		enumsv1.TokenType_TOKEN_TYPE_SIGN_IN_REVOKE.String(),
+		ttl,
+		map[string]any{
+			common.JwtTenantIDKey:  logModel.TenantID,
+			common.JwtSignLogIDKey: strconv.FormatInt(logModel.ID, 10),
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	link, err := url.Parse(cfg.RevokeUrl)
+	if err != nil {
+		return "", fmt.Errorf("invalid revoke url: %w", err)
+	}
+	values := link.Query()
+	values.Set(revokeLinkTokenParam, token.Token)
+	link.RawQuery = values.Encode()
+	return link.String(), nil
+}
+
+// revokeDeviceSessions 吊销设备上所有未过期的登录，会话对应的token收集到rv中
+func (u *UserService) revokeDeviceSessions(ctx context.Context, tx *query.Query, rv *revocation, uid int64, device string) error {
+	now := time.Now()
+	logQ := tx.UserSignLog
+	logs, err := logQ.WithContext(ctx).Where(
+		logQ.UID.Eq(uid),
+		logQ.Device.Eq(device),
+		logQ.Status.Eq(int16(enumsv1.SignInStatus_SIGN_IN_STATUS_OK)),
+		logQ.RefreshExpiredAt.Gt(now),
+	).Find()
+	if err != nil || len(logs) == 0 {
+		return err
+	}
+	ids := make([]int64, 0, len(logs))
+	for _, l := range logs {
+		ids = append(ids, l.ID)
+		rv.add(u.getBlockItemsByLog(l, now)...)
+	}
+	_, err = logQ.WithContext(ctx).Where(logQ.ID.In(ids...)).Update(logQ.Status, int16(enumsv1.SignInStatus_SIGN_IN_STATUS_REVOKED))
+	return err
+}
+
+func deviceModelToDevice(m *model.UserDevice) *userv1.Device {
+	d := &userv1.Device{
+		Id:           m.ID,
+		Device:       m.Device,
+		Trusted:      m.Trusted,
+		LastIp:       m.LastIP,
+		LastLocation: common.ParseLocationFromString(m.LastLocation),
+	}
+	if m.TrustedAt != nil {
+		d.TrustedAt = timestamppb.New(*m.TrustedAt)
+	}
+	if m.LastSeenAt != nil {
+		d.LastSeenAt = timestamppb.New(*m.LastSeenAt)
+	}
+	if m.CreatedAt != nil {
+		d.CreatedAt = timestamppb.New(*m.CreatedAt)
+	}
+	return d
+}
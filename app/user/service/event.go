@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/pkg/logx"
+	"github.com/byteflowing/base/pkg/redis"
+	"github.com/byteflowing/base/pkg/utils/idx"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+// 用户领域事件通过transactional outbox发布：
+// 业务在同一个事务中写入user_outbox，relay轮询未发布的事件投递到asynq
+// 任务类型为 user:event:<type>，e.g. user:event:signed_in，payload为proto序列化的userv1.UserEvent
+// 投递至少一次，消费方使用UserEvent.id做幂等，asynq的TaskID也使用event id，重复投递会被asynq去重
+const (
+	eventTaskFormat     = "user:event:%s" // 事件类型去掉USER_EVENT_TYPE_前缀后的小写
+	eventTypePrefix     = "USER_EVENT_TYPE_"
+	outboxRelayLockKey  = "outbox_relay"
+	outboxRelayLockTTL  = time.Minute
+	outboxTaskRetention = 24 * time.Hour
+
+	defaultOutboxPollInterval = time.Second
+	defaultOutboxBatchSize    = 100
+	defaultOutboxMaxAttempts  = 10
+	maxOutboxErrorLen         = 500
+)
+
+// EventTaskName 事件类型对应的asynq任务类型，下游注册handler时使用
+func EventTaskName(typ enumsv1.UserEventType) string {
+	return fmt.Sprintf(eventTaskFormat, strings.ToLower(strings.TrimPrefix(typ.String(), eventTypePrefix)))
+}
+
+// auditEvents 安全审计相关的事件，未开启outbox时每次丢弃都记录错误日志
+var auditEvents = map[enumsv1.UserEventType]bool{
+	enumsv1.UserEventType_USER_EVENT_TYPE_CONTACT_CHANGED:  true,
+	enumsv1.UserEventType_USER_EVENT_TYPE_PASSWORD_CHANGED: true,
+}
+
+// emitEvent 在业务事务中写入outbox，未开启outbox时不记录事件
+// 依赖事件迁移数据的功能（账号合并、游客合并）需要在调用前检查outbox是否开启
+func (u *UserService) emitEvent(ctx context.Context, tx *query.Query, typ enumsv1.UserEventType, tenantID string, uid int64, data proto.Message) error {
+	if u.cfg.Outbox == nil {
+		if auditEvents[typ] {
+			logx.CtxError(ctx, "outbox is not configured, audit event dropped", zap.String("type", typ.String()), zap.Int64("uid", uid))
+		}
+		return nil
+	}
+	id, err := idx.UUIDv7()
+	if err != nil {
+		return err
+	}
+	event := &userv1.UserEvent{
+		Id:         id,
+		Type:       typ,
+		TenantId:   tenantID,
+		Uid:        uid,
+		OccurredAt: timestamppb.Now(),
+	}
+	if data != nil {
+		if event.Data, err = anypb.New(data); err != nil {
+			return err
+		}
+	}
+	payload, err := proto.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return tx.UserOutbox.WithContext(ctx).Create(&model.UserOutbox{
+		EventID:  id,
+		TenantID: tenantID,
+		UID:      uid,
+		Type:     int16(typ),
+		Payload:  payload,
+	})
+}
+
+// startOutboxRelay 启动outbox的投递，多实例部署时只有拿到锁的实例投递
+func (u *UserService) startOutboxRelay() {
+	interval := u.cfg.Outbox.PollInterval.AsDuration()
+	if interval <= 0 {
+		interval = defaultOutboxPollInterval
+	}
+	lock := redis.NewLock(u.rdb, &redis.LockOption{
+		Prefix: u.cfg.KeyPrefix + ":lock",
+		Tries:  1,
+		TTL:    outboxRelayLockTTL,
+	})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			ctx := context.Background()
+			identifier, err := lock.Acquire(ctx, outboxRelayLockKey)
+			if err != nil {
+				continue
+			}
+			if err := u.relayEvents(ctx); err != nil {
+				logx.CtxError(ctx, "relay user events failed", zap.Error(err))
+			}
+			if err := u.cleanOutbox(ctx); err != nil {
+				logx.CtxError(ctx, "clean user outbox failed", zap.Error(err))
+			}
+			_ = lock.Release(ctx, outboxRelayLockKey, identifier)
+		}
+	}()
+}
+
+// relayEvents 按写入顺序投递一批未发布的事件，投递失败的事件在下次轮询时重试
+// 失败次数达到上限的事件不再投递，保留在表中（published_at为空）作为死信，由人工处理
+func (u *UserService) relayEvents(ctx context.Context) error {
+	batchSize := int(u.cfg.Outbox.BatchSize)
+	if batchSize <= 0 {
+		batchSize = defaultOutboxBatchSize
+	}
+	maxAttempts := int32(u.cfg.Outbox.MaxAttempts)
+	if maxAttempts <= 0 {
+		maxAttempts = defaultOutboxMaxAttempts
+	}
+	q := u.db.UserOutbox
+	rows, err := q.WithContext(ctx).Where(
+		q.PublishedAt.IsNull(),
+		q.Attempts.Lt(maxAttempts),
+	).Order(q.ID).Limit(batchSize).Find()
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		err := u.publishEvent(ctx, row)
+		if err != nil {
+			logx.CtxWarn(ctx, "publish user event failed", zap.String("eventID", row.EventID), zap.Error(err))
+			msg := err.Error()
+			if len(msg) > maxOutboxErrorLen {
+				msg = msg[:maxOutboxErrorLen]
+			}
+			if _, err := q.WithContext(ctx).Where(q.ID.Eq(row.ID)).UpdateSimple(
+				q.Attempts.Add(1),
+				q.LastError.Value(msg),
+			); err != nil {
+				return err
+			}
+			if row.Attempts+1 >= maxAttempts {
+				logx.CtxError(ctx, "user event dead-lettered after max attempts", zap.String("eventID", row.EventID), zap.Int32("attempts", row.Attempts+1))
+			}
+			continue
+		}
+		if _, err := q.WithContext(ctx).Where(q.ID.Eq(row.ID)).UpdateSimple(
+			q.Attempts.Add(1),
+			q.PublishedAt.Value(time.Now()),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *UserService) publishEvent(ctx context.Context, row *model.UserOutbox) error {
+	event := &userv1.UserEvent{}
+	if err := proto.Unmarshal(row.Payload, event); err != nil {
+		return err
+	}
+	opts := []asynq.Option{
+		asynq.TaskID(event.Id),
+		asynq.Retention(outboxTaskRetention),
+	}
+	if u.cfg.Outbox.Queue != "" {
+		opts = append(opts, asynq.Queue(u.cfg.Outbox.Queue))
+	}
+	err := u.queue.EnQueue(ctx, EventTaskName(event.Type), event, opts...)
+	// 上次已投递成功但没来得及标记
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return nil
+	}
+	return err
+}
+
+// cleanOutbox 删除已发布且超过保留时长的事件，未配置保留时长时不清理
+func (u *UserService) cleanOutbox(ctx context.Context) error {
+	retention := u.cfg.Outbox.Retention.AsDuration()
+	if retention <= 0 {
+		return nil
+	}
+	batchSize := int(u.cfg.Outbox.BatchSize)
+	if batchSize <= 0 {
+		batchSize = defaultOutboxBatchSize
+	}
+	var ids []int64
+	q := u.db.UserOutbox
+	if err := q.WithContext(ctx).Unscoped().Where(
+		q.PublishedAt.Lt(time.Now().Add(-retention)),
+	).Order(q.ID).Limit(batchSize).Pluck(q.ID, &ids); err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := q.WithContext(ctx).Unscoped().Where(q.ID.In(ids...)).Delete()
+	return err
+}
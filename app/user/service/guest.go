@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/byteflowing/base/app/user/auth"
+	"github.com/byteflowing/base/app/user/common"
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/ecode"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+// defaultGuestScope 未配置时游客token携带的scope，网关根据scope限制游客可以访问的接口
+const defaultGuestScope = "guest"
+
+// UpgradeGuest 游客绑定手机号或第三方身份后转为正式账号
+// 身份还没有对应的账号时直接把游客账号转为正式账号，uid不变
+// 身份已属于其他账号时把游客合并到该账号：注销游客账号并发布GUEST_MERGED事件，下游把游客uid下的数据迁移到目标账号
+// 合并依赖GUEST_MERGED事件迁移数据，未开启outbox时不能合并
+func (u *UserService) UpgradeGuest(ctx context.Context, req *userv1.UpgradeGuestReq) (*userv1.UpgradeGuestResp, error) {
+	if req.Source == enumsv1.UserSource_USER_SOURCE_UNSPECIFIED || req.Source == enumsv1.UserSource_USER_SOURCE_GUEST {
+		return nil, ecode.ErrParams
+	}
+	uid, _, err := u.parseSensitiveAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	phone := req.GetPhone()
+	if phone != nil {
+		if err := u.verifyPhoneCaptcha(ctx, phone.PhoneNumber, phone.CaptchaToken, phone.Captcha, enumsv1.MessageSceneType_MESSAGE_SCENE_TYPE_BIND_PHONE); err != nil {
+			return nil, err
+		}
+	}
+	var resp *userv1.UpgradeGuestResp
+	var alert *deviceAlert
+	rv := &revocation{}
+	err = u.db.Transaction(func(tx *query.Query) error {
+		guest, err := u.getUserAccount(ctx, tx, uid)
+		if err != nil {
+			return err
+		}
+		if guest.Source != int16(enumsv1.UserSource_USER_SOURCE_GUEST) {
+			return ecode.ErrUserNotGuest
+		}
+		if !common.IsUserValid(guest.Status) {
+			return ecode.ErrUserDisabled
+		}
+		var result *userv1.SignInResult
+		var signInType enumsv1.SignInType
+		switch {
+		case phone != nil:
+			signInType = enumsv1.SignInType_SIGN_IN_TYPE_PHONE_CAPTCHA
+			result, err = u.bindGuestPhone(ctx, tx, guest, phone)
+		case req.GetSignIn() != nil:
+			signInType = req.GetSignIn().SignInType
+			result, err = u.bindGuestIdentity(ctx, tx, guest, req.GetSignIn())
+		default:
+			return ecode.ErrParams
+		}
+		if err != nil {
+			return err
+		}
+		merged := result.User.GetUid() != guest.ID
+		if merged {
+			if err := u.mergeGuest(ctx, tx, rv, guest, result); err != nil {
+				return err
+			}
+		} else {
+			if result, err = u.convertGuest(ctx, tx, rv, guest, req.Source); err != nil {
+				return err
+			}
+		}
+		resp = &userv1.UpgradeGuestResp{Merged: merged}
+		// 合并到已有账号时，目标账号开启了两步验证仍需要完成验证
+		mfaToken, err := u.checkMfa(ctx, tx, result, signInType)
+		if err != nil {
+			return err
+		}
+		if mfaToken != "" {
+			resp.MfaRequired = true
+			resp.MfaToken = mfaToken
+			return nil
+		}
+		signIn, signInAlert, err := u.issueSignIn(ctx, tx, result.User, signInType, result.Identifier, req.Agent, req.ExtraJwtClaims, false)
+		if err != nil {
+			return err
+		}
+		resp.AccessToken = signIn.AccessToken
+		resp.RefreshToken = signIn.RefreshToken
+		resp.UserInfo = signIn.UserInfo
+		alert = signInAlert
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := u.addBlockItems(ctx, rv.items); err != nil {
+		return nil, err
+	}
+	u.sendDeviceAlert(ctx, resp.UserInfo, alert)
+	return resp, nil
+}
+
+// bindGuestPhone 手机号已属于租户下的其他账号时返回该账号，否则绑定到游客账号
+func (u *UserService) bindGuestPhone(ctx context.Context, tx *query.Query, guest *model.UserAccount, phone *userv1.GuestPhoneBinding) (*userv1.SignInResult, error) {
+	number := phone.PhoneNumber
+	accountQ := tx.UserAccount
+	owner, err := accountQ.WithContext(ctx).Where(
+		accountQ.TenantID.Eq(guest.TenantID),
+		accountQ.PhoneCountryCode.Eq(number.CountryCode),
+		accountQ.Phone.Eq(number.Number),
+	).Take()
+	if err == nil {
+		// 手机号未验证的账号不能证明归属，不合并
+		if !owner.PhoneVerified {
+			return nil, ecode.ErrUserPhoneExists
+		}
+		return &userv1.SignInResult{User: common.UserModelToUser(owner)}, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if _, err := accountQ.WithContext(ctx).Where(accountQ.ID.Eq(guest.ID)).UpdateSimple(
+		accountQ.PhoneCountryCode.Value(number.CountryCode),
+		accountQ.Phone.Value(number.Number),
+		accountQ.PhoneVerified.Value(true),
+	); err != nil {
+		return nil, err
+	}
+	return &userv1.SignInResult{User: common.UserModelToUser(guest)}, nil
+}
+
+// bindGuestIdentity 支持绑定的登录方式优先绑定到游客账号，身份已被其他账号绑定时通过登录得到该账号
+// 不支持绑定的登录方式直接登录，新创建的账号也会作为合并的目标
+func (u *UserService) bindGuestIdentity(ctx context.Context, tx *query.Query, guest *model.UserAccount, signIn *userv1.SignInReq) (*userv1.SignInResult, error) {
+	if signIn.SignInType == enumsv1.SignInType_SIGN_IN_TYPE_GUEST {
+		return nil, ecode.ErrParams
+	}
+	signIn.TenantId = guest.TenantID
+	provider, err := u.getAuthProvider(ctx, tx, guest.TenantID, signIn.SignInType)
+	if err != nil {
+		return nil, err
+	}
+	if linker, ok := provider.(auth.Linker); ok {
+		err := linker.Link(ctx, signIn, tx, guest)
+		if err == nil {
+			return &userv1.SignInResult{User: common.UserModelToUser(guest)}, nil
+		}
+		if !errors.Is(err, ecode.ErrUserAuthAlreadyLinked) {
+			return nil, err
+		}
+	}
+	result, err := provider.Authenticate(ctx, signIn, tx)
+	if err != nil {
+		return nil, err
+	}
+	if result.Created {
+		if err := u.emitEvent(ctx, tx, enumsv1.UserEventType_USER_EVENT_TYPE_SIGNED_UP, result.User.GetTenantId(), result.User.GetUid(), &userv1.UserSignedUpEvent{
+			User:       result.User,
+			SignInType: signIn.SignInType,
+			Agent:      signIn.Agent,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// convertGuest 游客账号原地转为正式账号，删除设备绑定并吊销游客token，之后需要使用新签发的token
+func (u *UserService) convertGuest(ctx context.Context, tx *query.Query, rv *revocation, guest *model.UserAccount, source enumsv1.UserSource) (*userv1.SignInResult, error) {
+	accountQ := tx.UserAccount
+	if _, err := accountQ.WithContext(ctx).Where(accountQ.ID.Eq(guest.ID)).Update(accountQ.Source, int16(source)); err != nil {
+		return nil, err
+	}
+	if err := u.deleteGuestAuth(ctx, tx, guest.ID); err != nil {
+		return nil, err
+	}
+	if err := u.revokeSessions(ctx, tx, rv, guest.ID, enumsv1.SignInStatus_SIGN_IN_STATUS_REVOKED); err != nil {
+		return nil, err
+	}
+	userAccount, err := u.getUserAccount(ctx, tx, guest.ID)
+	if err != nil {
+		return nil, err
+	}
+	user := common.UserModelToUser(userAccount)
+	if err := u.emitEvent(ctx, tx, enumsv1.UserEventType_USER_EVENT_TYPE_GUEST_UPGRADED, user.GetTenantId(), user.GetUid(), &userv1.UserGuestUpgradedEvent{
+		User: user,
+	}); err != nil {
+		return nil, err
+	}
+	return &userv1.SignInResult{User: user}, nil
+}
+
+// mergeGuest 注销游客账号，游客uid下的业务数据由下游消费GUEST_MERGED事件迁移
+// 未开启outbox时事件不会发布，游客的数据无法迁移，拒绝合并
+func (u *UserService) mergeGuest(ctx context.Context, tx *query.Query, rv *revocation, guest *model.UserAccount, target *userv1.SignInResult) error {
+	if u.cfg.Outbox == nil {
+		return ecode.ErrUserEventsDisabled
+	}
+	user := target.User
+	if user.GetTenantId() != guest.TenantID {
+		return ecode.ErrUserAuthInvalid
+	}
+	targetAccount, err := u.getUserAccount(ctx, tx, user.GetUid())
+	if err != nil {
+		return err
+	}
+	if !common.IsUserValid(targetAccount.Status) {
+		return ecode.ErrUserDisabled
+	}
+	if err := u.anonymizeAccount(ctx, tx, rv, guest.ID); err != nil {
+		return err
+	}
+	return u.emitEvent(ctx, tx, enumsv1.UserEventType_USER_EVENT_TYPE_GUEST_MERGED, guest.TenantID, user.GetUid(), &userv1.UserGuestMergedEvent{
+		GuestUid: guest.ID,
+		Uid:      user.GetUid(),
+	})
+}
+
+// deleteGuestAuth 删除游客的设备绑定，open_id上有唯一索引，需要物理删除
+func (u *UserService) deleteGuestAuth(ctx context.Context, tx *query.Query, uid int64) error {
+	q := tx.UserAuth
+	_, err := q.WithContext(ctx).Unscoped().Where(
+		q.UID.Eq(uid),
+		q.Type.Eq(int16(enumsv1.SignInType_SIGN_IN_TYPE_GUEST)),
+	).Delete()
+	return err
+}
+
+// guestScope 游客token的scope，多个scope以空格分隔
+func (u *UserService) guestScope() string {
+	if len(u.cfg.GuestScopes) == 0 {
+		return defaultGuestScope
+	}
+	return strings.Join(u.cfg.GuestScopes, " ")
+}
+
+func isGuest(user *userv1.User) bool {
+	return user.GetSource() == enumsv1.UserSource_USER_SOURCE_GUEST
+}
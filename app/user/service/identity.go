@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/byteflowing/base/app/user/auth"
+	"github.com/byteflowing/base/app/user/common"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/ecode"
+	"github.com/byteflowing/base/pkg/logx"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+// LinkAuth 已登录的用户绑定第三方登录方式，e.g. 手机号注册的用户绑定Apple账号
+func (u *UserService) LinkAuth(ctx context.Context, req *userv1.LinkAuthReq) (*userv1.LinkAuthResp, error) {
+	uid, _, err := u.parseSensitiveAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	signIn := req.SignIn
+	if signIn == nil {
+		return nil, ecode.ErrParams
+	}
+	err = u.db.Transaction(func(tx *query.Query) error {
+		userAccount, err := u.getUserAccount(ctx, tx, uid)
+		if err != nil {
+			return err
+		}
+		if !common.IsUserValid(userAccount.Status) {
+			return ecode.ErrUserDisabled
+		}
+		// 游客需要通过UpgradeGuest绑定身份
+		if userAccount.Source == int16(enumsv1.UserSource_USER_SOURCE_GUEST) {
+			return ecode.ErrUserGuestNotAllowed
+		}
+		provider, err := u.getAuthProvider(ctx, tx, userAccount.TenantID, signIn.SignInType)
+		if err != nil {
+			return err
+		}
+		linker, ok := provider.(auth.Linker)
+		if !ok {
+			return ecode.ErrUserAuthInvalid
+		}
+		q := tx.UserAuth
+		count, err := q.WithContext(ctx).Where(q.UID.Eq(uid), q.Type.Eq(int16(signIn.SignInType))).Count()
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ecode.ErrUserAuthAlreadyLinked
+		}
+		return linker.Link(ctx, signIn, tx, userAccount)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.LinkAuthResp{}, nil
+}
+
+// UnlinkAuth 用户解绑第三方登录方式，解绑后会撤销第三方的用户凭证
+// 解绑后用户必须还有其他可用的登录方式
+func (u *UserService) UnlinkAuth(ctx context.Context, req *userv1.UnlinkAuthReq) (*userv1.UnlinkAuthResp, error) {
+	uid, _, err := u.parseSensitiveAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var identifiers []string
+	var tenantID string
+	err = u.db.Transaction(func(tx *query.Query) error {
+		userAccount, err := u.getUserAccount(ctx, tx, uid)
+		if err != nil {
+			return err
+		}
+		tenantID = userAccount.TenantID
+		q := tx.UserAuth
+		auths, err := q.WithContext(ctx).Where(q.UID.Eq(uid), q.Type.Eq(int16(req.SignInType))).Find()
+		if err != nil {
+			return err
+		}
+		if len(auths) == 0 {
+			return ecode.ErrUserAuthNotLinked
+		}
+		others, err := q.WithContext(ctx).Where(q.UID.Eq(uid), q.Type.Neq(int16(req.SignInType))).Count()
+		if err != nil {
+			return err
+		}
+		hasCredential := userAccount.Password != nil || userAccount.PhoneVerified || userAccount.EmailVerified
+		if others == 0 && !hasCredential {
+			return ecode.ErrUserAuthLastOne
+		}
+		for _, a := range auths {
+			identifiers = append(identifiers, a.OpenID)
+		}
+		// open_id上有唯一索引，需要物理删除以便重新绑定
+		_, err = q.WithContext(ctx).Unscoped().Where(q.UID.Eq(uid), q.Type.Eq(int16(req.SignInType))).Delete()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, identifier := range identifiers {
+		u.revokeThirdParty(ctx, tenantID, req.SignInType, identifier)
+	}
+	return &userv1.UnlinkAuthResp{}, nil
+}
+
+// revokeThirdParty 撤销第三方登录方式的用户凭证，失败只记录日志不影响主流程
+func (u *UserService) revokeThirdParty(ctx context.Context, tenantID string, signInType enumsv1.SignInType, identifier string) {
+	if identifier == "" {
+		return
+	}
+	provider, err := u.getAuthProvider(ctx, u.db, tenantID, signInType)
+	if err != nil {
+		return
+	}
+	revoker, ok := provider.(auth.Revoker)
+	if !ok {
+		return
+	}
+	if err := revoker.Revoke(ctx, identifier); err != nil {
+		logx.CtxWarn(ctx, "revoke third party token failed",
+			zap.String("signInType", signInType.String()),
+			zap.String("identifier", identifier),
+			zap.Error(err),
+		)
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/byteflowing/base/app/user/common"
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/ecode"
+	"github.com/byteflowing/base/pkg/blocklist"
+	"github.com/byteflowing/base/pkg/jwt"
+	"github.com/byteflowing/base/pkg/utils/trans"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+const defaultImpersonationTtl = 15 * time.Minute
+
+// ImpersonatePermission 模拟登录需要操作人在目标用户所在租户下拥有的权限
+const ImpersonatePermission = "user:impersonate"
+
+// ImpersonateUser 客服以用户的身份查看应用，签发目标用户的短期access token
+// 操作人需要和目标用户在同一个租户下，并且拥有ImpersonatePermission权限
+// token的act claim记录操作人uid，不签发refresh token，不能进行修改密码、注销账号等敏感操作
+// 每次签发都记录到user_impersonation表
+func (u *UserService) ImpersonateUser(ctx context.Context, req *userv1.ImpersonateUserReq) (*userv1.ImpersonateUserResp, error) {
+	if req.Reason == "" {
+		return nil, ecode.ErrParams
+	}
+	actorUID, _, err := u.parseSensitiveAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	if actorUID == req.Uid {
+		return nil, ecode.ErrUserImpersonateSelf
+	}
+	var token *jwt.Token
+	err = u.db.Transaction(func(tx *query.Query) error {
+		actor, err := u.getUserAccount(ctx, tx, actorUID)
+		if err != nil {
+			return err
+		}
+		if !common.IsUserValid(actor.Status) {
+			return ecode.ErrUserDisabled
+		}
+		userAccount, err := u.getUserAccount(ctx, tx, req.Uid)
+		if err != nil {
+			return err
+		}
+		if !common.IsUserValid(userAccount.Status) {
+			return ecode.ErrUserDisabled
+		}
+		if actor.TenantID != userAccount.TenantID {
+			return ecode.ErrUserPermissionDenied
+		}
+		allowed, err := u.hasPermission(ctx, tx, userAccount.TenantID, actorUID, ImpersonatePermission)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return ecode.ErrUserPermissionDenied
+		}
+		tenant, err := u.getTenant(ctx, tx, userAccount.TenantID)
+		if err != nil {
+			return err
+		}
+		extraClaims, err := u.userClaims(ctx, tx, tenant, common.UserModelToUser(userAccount), nil)
+		if err != nil {
+			return err
+		}
+		extraClaims[common.JwtActKey] = map[string]any{"sub": strconv.FormatInt(actorUID, 10)}
+		if token, err = u.token.Generate(
+			strconv.FormatInt(userAccount.ID, 10),
+			enumsv1.TokenType_TOKEN_TYPE_ACCESS.String(),
+			u.impersonationTtl(),
+			extraClaims,
+		); err != nil {
+			return err
+		}
+		return tx.UserImpersonation.WithContext(ctx).Create(&model.UserImpersonation{
+			TenantID:  userAccount.TenantID,
+			ActorUID:  actorUID,
+			UID:       userAccount.ID,
+			Reason:    req.Reason,
+			Jti:       token.Jti,
+			IP:        req.Ip,
+			ExpiredAt: trans.Ref(token.Exp),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.ImpersonateUserResp{
+		AccessToken: token.Token,
+		ExpiredAt:   timestamppb.New(token.Exp),
+	}, nil
+}
+
+func (u *UserService) impersonationTtl() time.Duration {
+	if u.cfg.Impersonation != nil && u.cfg.Impersonation.Ttl != nil {
+		return u.cfg.Impersonation.Ttl.AsDuration()
+	}
+	return defaultImpersonationTtl
+}
+
+// parseSensitiveAccessToken 修改凭证、注销账号等敏感操作使用，拒绝模拟登录的token
+func (u *UserService) parseSensitiveAccessToken(ctx context.Context, token string) (uid int64, claims jwtv5.MapClaims, err error) {
+	uid, claims, err = u.parseAccessToken(ctx, token)
+	if err != nil {
+		return 0, nil, err
+	}
+	if common.GetTokenActorUID(claims) != 0 {
+		return 0, nil, ecode.ErrUserImpersonationNotAllowed
+	}
+	return uid, claims, nil
+}
+
+// revokeImpersonations 把用户未过期的模拟登录token收集到rv中
+func (u *UserService) revokeImpersonations(ctx context.Context, tx *query.Query, rv *revocation, uid int64, now time.Time) error {
+	q := tx.UserImpersonation
+	items, err := q.WithContext(ctx).Where(q.UID.Eq(uid), q.ExpiredAt.Gt(now)).Find()
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	for _, item := range items {
+		rv.add(&blocklist.BlockItem{
+			Target: item.Jti,
+			TTL:    item.ExpiredAt.Sub(now),
+		})
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/byteflowing/base/app/user/common"
+	"github.com/byteflowing/base/ecode"
+	"github.com/byteflowing/base/pkg/cache"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+	jwtv5 "github.com/golang-jwt/jwt/v5"
+)
+
+// RFC 7662 token_type_hint
+const (
+	tokenTypeHintAccess  = "access_token"
+	tokenTypeHintRefresh = "refresh_token"
+)
+
+// IntrospectionScope HTTP自省接口要求调用方的服务账号token具有这个scope
+const IntrospectionScope = "token:introspect"
+
+// introspectTokenTypes 未指定hint时依次尝试的token类型
+var introspectTokenTypes = []enumsv1.TokenType{
+	enumsv1.TokenType_TOKEN_TYPE_ACCESS,
+	enumsv1.TokenType_TOKEN_TYPE_SERVICE,
+	enumsv1.TokenType_TOKEN_TYPE_REFRESH,
+}
+
+// validationCache 进程内缓存未被吊销的jti，网关高频校验token时减少对redis的访问
+// 缓存命中时不再检查黑名单，依赖本地黑名单的同步消息在吊销时清理缓存，消息丢失时最多在ttl内仍被判定为有效
+type validationCache struct {
+	cache *cache.Cache
+	ttl   time.Duration
+}
+
+func newValidationCache(config *userv1.ValidationCacheConfig) *validationCache {
+	return &validationCache{
+		cache: cache.New(config.Cache),
+		ttl:   config.Ttl.AsDuration(),
+	}
+}
+
+func (c *validationCache) valid(jti string) bool {
+	if c == nil {
+		return false
+	}
+	exists, _ := c.cache.Exists(jti)
+	return exists
+}
+
+// set 缓存时间不超过token的剩余有效期
+func (c *validationCache) set(jti string, exp *time.Time) {
+	if c == nil {
+		return
+	}
+	ttl := c.ttl
+	if exp != nil {
+		if remain := time.Until(*exp); remain < ttl {
+			ttl = remain
+		}
+	}
+	seconds := int(ttl / time.Second)
+	if seconds <= 0 {
+		return
+	}
+	_ = c.cache.Set(jti, nil, seconds)
+}
+
+func (c *validationCache) forget(jtis ...string) {
+	if c == nil {
+		return
+	}
+	for _, jti := range jtis {
+		c.cache.Delete(jti)
+	}
+}
+
+// IntrospectToken RFC 7662风格的token自省，token无效、过期或已吊销时返回active=false而不是错误
+func (u *UserService) IntrospectToken(ctx context.Context, req *userv1.IntrospectTokenReq) (*userv1.IntrospectTokenResp, error) {
+	for _, typ := range introspectCandidates(req.TokenTypeHint) {
+		claims, err := u.token.Parse(req.Token, typ.String())
+		if err != nil {
+			continue
+		}
+		if common.GetJwtJti(claims) == "" {
+			break
+		}
+		blocked, err := u.isJtiBlocked(ctx, claims)
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			break
+		}
+		return &userv1.IntrospectTokenResp{
+			Active: true,
+			Scope:  strings.Join(common.GetTokenScopes(claims), " "),
+			Claims: common.ClaimsToJwtClaims(claims, req.ExtraKey),
+		}, nil
+	}
+	return &userv1.IntrospectTokenResp{Active: false}, nil
+}
+
+// AuthenticateIntrospectionClient 校验HTTP自省接口调用方的服务账号token
+func (u *UserService) AuthenticateIntrospectionClient(ctx context.Context, token string) error {
+	claims, err := u.token.Parse(token, enumsv1.TokenType_TOKEN_TYPE_SERVICE.String())
+	if err != nil {
+		return ecode.ErrUserTokenInvalid
+	}
+	blocked, err := u.isJtiBlocked(ctx, claims)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ecode.ErrUserTokenInvalid
+	}
+	if !slices.Contains(common.GetTokenScopes(claims), IntrospectionScope) {
+		return ecode.ErrUserScopeNotAllowed
+	}
+	return nil
+}
+
+// BatchValidateTokens 批量校验同一类型的token，结果和请求中token的顺序一致
+// 无效的token对应的结果valid=false，不影响其他token
+func (u *UserService) BatchValidateTokens(ctx context.Context, req *userv1.BatchValidateTokensReq) (*userv1.BatchValidateTokensResp, error) {
+	results := make([]*userv1.TokenValidationResult, len(req.Tokens))
+	parsed := make([]jwtv5.MapClaims, len(req.Tokens))
+	var jtis []string
+	var indexes []int
+	for i, token := range req.Tokens {
+		results[i] = &userv1.TokenValidationResult{}
+		claims, err := u.token.Parse(token, req.Type.String())
+		if err != nil {
+			continue
+		}
+		jti := common.GetJwtJti(claims)
+		if jti == "" {
+			continue
+		}
+		parsed[i] = claims
+		if u.validCache.valid(jti) {
+			continue
+		}
+		jtis = append(jtis, jti)
+		indexes = append(indexes, i)
+	}
+	blocked, err := u.blk.BatchExists(ctx, jtis)
+	if err != nil {
+		return nil, err
+	}
+	for k, i := range indexes {
+		if blocked[k] {
+			parsed[i] = nil
+			continue
+		}
+		u.validCache.set(jtis[k], common.GetJwtExp(parsed[i]))
+	}
+	for i, claims := range parsed {
+		if claims == nil {
+			continue
+		}
+		results[i].Valid = true
+		results[i].Claims = common.ClaimsToJwtClaims(claims, req.ExtraKey)
+	}
+	return &userv1.BatchValidateTokensResp{Type: req.Type, Results: results}, nil
+}
+
+// isJtiBlocked 检查token是否已被吊销，开启validation cache时优先使用本地缓存
+func (u *UserService) isJtiBlocked(ctx context.Context, claims jwtv5.MapClaims) (bool, error) {
+	jti := common.GetJwtJti(claims)
+	if jti == "" {
+		return false, ecode.ErrUserTokenInvalid
+	}
+	if u.validCache.valid(jti) {
+		return false, nil
+	}
+	blocked, err := u.blk.Exists(ctx, jti)
+	if err != nil {
+		return false, err
+	}
+	if !blocked {
+		u.validCache.set(jti, common.GetJwtExp(claims))
+	}
+	return blocked, nil
+}
+
+func introspectCandidates(hint string) []enumsv1.TokenType {
+	var first enumsv1.TokenType
+	switch hint {
+	case tokenTypeHintAccess:
+		first = enumsv1.TokenType_TOKEN_TYPE_ACCESS
+	case tokenTypeHintRefresh:
+		first = enumsv1.TokenType_TOKEN_TYPE_REFRESH
+	default:
+		first = enumsv1.TokenType(enumsv1.TokenType_value[hint])
+	}
+	// mfa等内部使用的token不允许自省
+	if !slices.Contains(introspectTokenTypes, first) {
+		return introspectTokenTypes
+	}
+	candidates := []enumsv1.TokenType{first}
+	for _, typ := range introspectTokenTypes {
+		if typ != first {
+			candidates = append(candidates, typ)
+		}
+	}
+	return candidates
+}
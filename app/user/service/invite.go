@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gen"
+	"gorm.io/gorm"
+
+	"github.com/byteflowing/base/app/user/common"
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/ecode"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+// CreateInviteCode 用户创建自己的邀请码，未指定使用次数和有效期时使用配置的默认值
+// 配置了默认值时，用户指定的使用次数和有效期不能超过默认值
+func (u *UserService) CreateInviteCode(ctx context.Context, req *userv1.CreateInviteCodeReq) (*userv1.CreateInviteCodeResp, error) {
+	cfg := u.cfg.Invite
+	if cfg == nil {
+		return nil, ecode.ErrUnImplemented
+	}
+	if req.GetMaxUses() < 0 || req.Ttl.AsDuration() < 0 {
+		return nil, ecode.ErrParams
+	}
+	uid, _, err := u.parseAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var m *model.UserInviteCode
+	err = u.db.Transaction(func(tx *query.Query) error {
+		userAccount, err := u.getUserAccount(ctx, tx, uid)
+		if err != nil {
+			return err
+		}
+		if !common.IsUserValid(userAccount.Status) {
+			return ecode.ErrUserDisabled
+		}
+		if userAccount.Source == int16(enumsv1.UserSource_USER_SOURCE_GUEST) {
+			return ecode.ErrUserGuestNotAllowed
+		}
+		if cfg.MaxCodesPerUser > 0 {
+			q := tx.UserInviteCode
+			count, err := q.WithContext(ctx).Where(
+				q.OwnerUID.Eq(uid),
+				q.Status.Eq(int16(enumsv1.InviteCodeStatus_INVITE_CODE_STATUS_OK)),
+			).Count()
+			if err != nil {
+				return err
+			}
+			if count >= int64(cfg.MaxCodesPerUser) {
+				return ecode.ErrUserInviteCodeLimit
+			}
+		}
+		maxUses := cfg.DefaultMaxUses
+		if req.MaxUses != nil {
+			maxUses = capInviteLimit(req.GetMaxUses(), cfg.DefaultMaxUses)
+		}
+		ttl := cfg.DefaultTtl.AsDuration()
+		if req.Ttl != nil {
+			ttl = capInviteLimit(req.Ttl.AsDuration(), cfg.DefaultTtl.AsDuration())
+		}
+		m, err = u.newInviteCode(ctx, tx, userAccount.TenantID, uid, "", maxUses, ttl)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.CreateInviteCodeResp{Code: inviteCodeModelToInviteCode(m)}, nil
+}
+
+// CreateCampaignInviteCode 管理后台为活动创建邀请码，活动邀请码没有邀请人
+func (u *UserService) CreateCampaignInviteCode(ctx context.Context, req *userv1.CreateCampaignInviteCodeReq) (*userv1.CreateCampaignInviteCodeResp, error) {
+	if u.cfg.Invite == nil {
+		return nil, ecode.ErrUnImplemented
+	}
+	if req.Campaign == "" || req.MaxUses < 0 {
+		return nil, ecode.ErrParams
+	}
+	if req.TenantId != "" {
+		if _, err := u.getTenantModel(ctx, req.TenantId); err != nil {
+			return nil, err
+		}
+	}
+	var ttl time.Duration
+	if req.ExpiredAt != nil {
+		if ttl = time.Until(req.ExpiredAt.AsTime()); ttl <= 0 {
+			return nil, ecode.ErrParams
+		}
+	}
+	m, err := u.newInviteCode(ctx, u.db, req.TenantId, 0, req.Campaign, req.MaxUses, ttl)
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.CreateCampaignInviteCodeResp{Code: inviteCodeModelToInviteCode(m)}, nil
+}
+
+// DisableInviteCode 用户禁用自己的邀请码，禁用后邀请码不能再使用，已经记录的邀请关系不受影响
+func (u *UserService) DisableInviteCode(ctx context.Context, req *userv1.DisableInviteCodeReq) (*userv1.DisableInviteCodeResp, error) {
+	uid, _, err := u.parseAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	q := u.db.UserInviteCode
+	if err := u.disableInviteCode(ctx, q.ID.Eq(req.Id), q.OwnerUID.Eq(uid)); err != nil {
+		return nil, err
+	}
+	return &userv1.DisableInviteCodeResp{}, nil
+}
+
+// AdminDisableInviteCode 管理后台禁用任意邀请码，包括活动邀请码
+func (u *UserService) AdminDisableInviteCode(ctx context.Context, req *userv1.AdminDisableInviteCodeReq) (*userv1.AdminDisableInviteCodeResp, error) {
+	q := u.db.UserInviteCode
+	if err := u.disableInviteCode(ctx, q.ID.Eq(req.Id)); err != nil {
+		return nil, err
+	}
+	return &userv1.AdminDisableInviteCodeResp{}, nil
+}
+
+func (u *UserService) disableInviteCode(ctx context.Context, conds ...gen.Condition) error {
+	q := u.db.UserInviteCode
+	m, err := q.WithContext(ctx).Where(conds...).Take()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ecode.ErrUserInviteCodeNotFound
+		}
+		return err
+	}
+	_, err = q.WithContext(ctx).Where(q.ID.Eq(m.ID)).Update(q.Status, int16(enumsv1.InviteCodeStatus_INVITE_CODE_STATUS_DISABLED))
+	return err
+}
+
+func (u *UserService) ListMyInviteCodes(ctx context.Context, req *userv1.ListMyInviteCodesReq) (*userv1.ListMyInviteCodesResp, error) {
+	uid, _, err := u.parseAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	q := u.db.UserInviteCode
+	models, err := q.WithContext(ctx).Where(q.OwnerUID.Eq(uid)).Order(q.ID.Desc()).Find()
+	if err != nil {
+		return nil, err
+	}
+	codes := make([]*userv1.InviteCode, 0, len(models))
+	for _, m := range models {
+		codes = append(codes, inviteCodeModelToInviteCode(m))
+	}
+	return &userv1.ListMyInviteCodesResp{Codes: codes}, nil
+}
+
+// GetReferralStats 用户的邀请统计：累计邀请人数、最近一次邀请时间以及每个邀请码的使用情况
+func (u *UserService) GetReferralStats(ctx context.Context, req *userv1.GetReferralStatsReq) (*userv1.GetReferralStatsResp, error) {
+	uid, _, err := u.parseAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	refQ := u.db.UserReferral
+	total, err := refQ.WithContext(ctx).Where(refQ.ReferrerUID.Eq(uid)).Count()
+	if err != nil {
+		return nil, err
+	}
+	resp := &userv1.GetReferralStatsResp{InvitedCount: total}
+	if total > 0 {
+		last, err := refQ.WithContext(ctx).Where(refQ.ReferrerUID.Eq(uid)).Order(refQ.ID.Desc()).First()
+		if err != nil {
+			return nil, err
+		}
+		if last.CreatedAt != nil {
+			resp.LastInvitedAt = timestamppb.New(*last.CreatedAt)
+		}
+	}
+	q := u.db.UserInviteCode
+	models, err := q.WithContext(ctx).Where(q.OwnerUID.Eq(uid)).Order(q.ID.Desc()).Find()
+	if err != nil {
+		return nil, err
+	}
+	resp.Codes = make([]*userv1.InviteCode, 0, len(models))
+	for _, m := range models {
+		resp.Codes = append(resp.Codes, inviteCodeModelToInviteCode(m))
+	}
+	return resp, nil
+}
+
+// redeemInviteCode 新注册的用户使用邀请码，记录邀请关系并发布REFERRED事件
+// 使用次数在同一条update中检查并累加，并发使用时不会超过上限
+func (u *UserService) redeemInviteCode(ctx context.Context, tx *query.Query, user *userv1.User, code string) error {
+	if u.cfg.Invite == nil {
+		return ecode.ErrUnImplemented
+	}
+	q := tx.UserInviteCode
+	m, err := q.WithContext(ctx).Where(q.TenantID.Eq(user.GetTenantId()), q.Code.Eq(code)).Take()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ecode.ErrUserInviteCodeInvalid
+		}
+		return err
+	}
+	if m.Status != int16(enumsv1.InviteCodeStatus_INVITE_CODE_STATUS_OK) {
+		return ecode.ErrUserInviteCodeInvalid
+	}
+	now := time.Now()
+	if m.ExpiredAt != nil && !now.Before(*m.ExpiredAt) {
+		return ecode.ErrUserInviteCodeExpired
+	}
+	conds := []gen.Condition{q.ID.Eq(m.ID), q.Status.Eq(int16(enumsv1.InviteCodeStatus_INVITE_CODE_STATUS_OK))}
+	if m.MaxUses > 0 {
+		conds = append(conds, q.UsedCount.Lt(m.MaxUses))
+	}
+	info, err := q.WithContext(ctx).Where(conds...).UpdateSimple(q.UsedCount.Add(1))
+	if err != nil {
+		return err
+	}
+	if info.RowsAffected == 0 {
+		return ecode.ErrUserInviteCodeExhausted
+	}
+	if err := tx.UserReferral.WithContext(ctx).Create(&model.UserReferral{
+		TenantID:    user.GetTenantId(),
+		UID:         user.GetUid(),
+		ReferrerUID: m.OwnerUID,
+		CodeID:      m.ID,
+		Campaign:    m.Campaign,
+	}); err != nil {
+		return err
+	}
+	return u.emitEvent(ctx, tx, enumsv1.UserEventType_USER_EVENT_TYPE_REFERRED, user.GetTenantId(), user.GetUid(), &userv1.UserReferredEvent{
+		ReferrerUid: m.OwnerUID,
+		Code:        m.Code,
+		Campaign:    m.Campaign,
+	})
+}
+
+// newInviteCode 使用shortid生成邀请码，ttl<=0表示不过期，maxUses<=0表示不限次数
+func (u *UserService) newInviteCode(
+	ctx context.Context,
+	tx *query.Query,
+	tenantID string,
+	ownerUID int64,
+	campaign string,
+	maxUses int32,
+	ttl time.Duration,
+) (*model.UserInviteCode, error) {
+	code, err := u.ids.GetShortID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m := &model.UserInviteCode{
+		TenantID: tenantID,
+		Code:     code,
+		OwnerUID: ownerUID,
+		Campaign: campaign,
+		Status:   int16(enumsv1.InviteCodeStatus_INVITE_CODE_STATUS_OK),
+		MaxUses:  max(maxUses, 0),
+	}
+	if ttl > 0 {
+		expiredAt := time.Now().Add(ttl)
+		m.ExpiredAt = &expiredAt
+	}
+	if err := tx.UserInviteCode.WithContext(ctx).Create(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func inviteCodeModelToInviteCode(m *model.UserInviteCode) *userv1.InviteCode {
+	code := &userv1.InviteCode{
+		Id:        m.ID,
+		TenantId:  m.TenantID,
+		Code:      m.Code,
+		OwnerUid:  m.OwnerUID,
+		Campaign:  m.Campaign,
+		Status:    enumsv1.InviteCodeStatus(m.Status),
+		MaxUses:   m.MaxUses,
+		UsedCount: m.UsedCount,
+	}
+	if m.ExpiredAt != nil {
+		code.ExpiredAt = timestamppb.New(*m.ExpiredAt)
+	}
+	if m.CreatedAt != nil {
+		code.CreatedAt = timestamppb.New(*m.CreatedAt)
+	}
+	return code
+}
+
+// capInviteLimit 用户指定的使用次数或有效期不能超过配置的上限，0表示不限制
+// 配置了上限时，用户传0（不限制）也会被限制为上限
+func capInviteLimit[T int32 | time.Duration](v, limit T) T {
+	if limit > 0 && (v == 0 || v > limit) {
+		return limit
+	}
+	return v
+}
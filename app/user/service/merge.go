@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+
+	"gorm.io/gen/field"
+
+	"github.com/byteflowing/base/app/user/common"
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/ecode"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+// MergeAccounts 管理后台合并同一个人的重复账号，source合并到target后被注销
+func (u *UserService) MergeAccounts(ctx context.Context, req *userv1.MergeAccountsReq) (*userv1.MergeAccountsResp, error) {
+	user, err := u.mergeAccounts(ctx, req.SourceUid, req.TargetUid, enumsv1.MergeInitiator_MERGE_INITIATOR_ADMIN)
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.MergeAccountsResp{User: user}, nil
+}
+
+// MergeMyAccounts 用户同时持有两个账号的access token时，可以把source合并到当前账号
+func (u *UserService) MergeMyAccounts(ctx context.Context, req *userv1.MergeMyAccountsReq) (*userv1.MergeMyAccountsResp, error) {
+	targetUID, _, err := u.parseSensitiveAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	sourceUID, _, err := u.parseSensitiveAccessToken(ctx, req.SourceAccessToken)
+	if err != nil {
+		return nil, err
+	}
+	user, err := u.mergeAccounts(ctx, sourceUID, targetUID, enumsv1.MergeInitiator_MERGE_INITIATOR_USER)
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.MergeMyAccountsResp{User: user}, nil
+}
+
+// mergeAccounts 在一个事务中把source的第三方身份、登录日志、设备、角色、邀请码和邀请关系转移到target
+// target没有已验证的手机号、邮箱或者没有密码时使用source的，之后注销source并吊销其所有会话
+// 其他业务数据由下游消费ACCOUNTS_MERGED事件迁移，未开启outbox时不能合并
+func (u *UserService) mergeAccounts(ctx context.Context, sourceUID, targetUID int64, initiator enumsv1.MergeInitiator) (*userv1.User, error) {
+	if sourceUID == 0 || targetUID == 0 || sourceUID == targetUID {
+		return nil, ecode.ErrParams
+	}
+	if u.cfg.Outbox == nil {
+		return nil, ecode.ErrUserEventsDisabled
+	}
+	var user *userv1.User
+	rv := &revocation{}
+	err := u.db.Transaction(func(tx *query.Query) error {
+		source, err := u.getUserAccount(ctx, tx, sourceUID)
+		if err != nil {
+			return err
+		}
+		target, err := u.getUserAccount(ctx, tx, targetUID)
+		if err != nil {
+			return err
+		}
+		if source.TenantID != target.TenantID {
+			return ecode.ErrUserMergeConflict
+		}
+		if !common.IsUserValid(target.Status) {
+			return ecode.ErrUserDisabled
+		}
+		// 用户发起的合并不能用于摆脱被禁用的账号，管理员合并不受限制
+		if initiator == enumsv1.MergeInitiator_MERGE_INITIATOR_USER && !common.IsUserValid(source.Status) {
+			return ecode.ErrUserDisabled
+		}
+		if err := u.revokeSessions(ctx, tx, rv, sourceUID, enumsv1.SignInStatus_SIGN_IN_STATUS_REVOKED); err != nil {
+			return err
+		}
+		if err := u.moveAuths(ctx, tx, sourceUID, targetUID); err != nil {
+			return err
+		}
+		logQ := tx.UserSignLog
+		if _, err := logQ.WithContext(ctx).Unscoped().Where(logQ.UID.Eq(sourceUID)).Update(logQ.UID, targetUID); err != nil {
+			return err
+		}
+		if err := u.moveDevices(ctx, tx, sourceUID, targetUID); err != nil {
+			return err
+		}
+		if err := u.moveCredentials(ctx, tx, source, target); err != nil {
+			return err
+		}
+		if err := u.moveRoleBindings(ctx, tx, sourceUID, targetUID); err != nil {
+			return err
+		}
+		inviteQ := tx.UserInviteCode
+		if _, err := inviteQ.WithContext(ctx).Where(inviteQ.OwnerUID.Eq(sourceUID)).Update(inviteQ.OwnerUID, targetUID); err != nil {
+			return err
+		}
+		if err := u.moveReferrals(ctx, tx, sourceUID, targetUID); err != nil {
+			return err
+		}
+		if err := u.anonymizeAccount(ctx, tx, rv, sourceUID); err != nil {
+			return err
+		}
+		merged, err := u.getUserAccount(ctx, tx, targetUID)
+		if err != nil {
+			return err
+		}
+		user = common.UserModelToUser(merged)
+		return u.emitEvent(ctx, tx, enumsv1.UserEventType_USER_EVENT_TYPE_ACCOUNTS_MERGED, target.TenantID, targetUID, &userv1.UserAccountsMergedEvent{
+			SourceUid: sourceUID,
+			TargetUid: targetUID,
+			Initiator: initiator,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := u.addBlockItems(ctx, rv.items); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// moveAuths 每种登录方式每个账号只能绑定一个身份，两个账号绑定了同一种登录方式时不能合并
+// 游客的设备绑定不转移，由注销流程删除
+func (u *UserService) moveAuths(ctx context.Context, tx *query.Query, sourceUID, targetUID int64) error {
+	q := tx.UserAuth
+	guestType := int16(enumsv1.SignInType_SIGN_IN_TYPE_GUEST)
+	var sourceTypes []int16
+	if err := q.WithContext(ctx).Where(q.UID.Eq(sourceUID), q.Type.Neq(guestType)).Pluck(q.Type, &sourceTypes); err != nil {
+		return err
+	}
+	if len(sourceTypes) == 0 {
+		return nil
+	}
+	conflicts, err := q.WithContext(ctx).Where(q.UID.Eq(targetUID), q.Type.In(sourceTypes...)).Count()
+	if err != nil {
+		return err
+	}
+	if conflicts > 0 {
+		return ecode.ErrUserMergeConflict
+	}
+	_, err = q.WithContext(ctx).Where(q.UID.Eq(sourceUID), q.Type.Neq(guestType)).Update(q.UID, targetUID)
+	return err
+}
+
+// moveDevices target已有的设备保留target的记录
+func (u *UserService) moveDevices(ctx context.Context, tx *query.Query, sourceUID, targetUID int64) error {
+	q := tx.UserDevice
+	var hashes []string
+	if err := q.WithContext(ctx).Where(q.UID.Eq(targetUID)).Pluck(q.DeviceHash, &hashes); err != nil {
+		return err
+	}
+	move := q.WithContext(ctx).Where(q.UID.Eq(sourceUID))
+	if len(hashes) > 0 {
+		move = move.Where(q.DeviceHash.NotIn(hashes...))
+	}
+	if _, err := move.Update(q.UID, targetUID); err != nil {
+		return err
+	}
+	_, err := q.WithContext(ctx).Unscoped().Where(q.UID.Eq(sourceUID)).Delete()
+	return err
+}
+
+// moveRoleBindings target已有的角色保留target的绑定
+func (u *UserService) moveRoleBindings(ctx context.Context, tx *query.Query, sourceUID, targetUID int64) error {
+	q := tx.UserRoleBinding
+	var roleIDs []int64
+	if err := q.WithContext(ctx).Where(q.UID.Eq(targetUID)).Pluck(q.RoleID, &roleIDs); err != nil {
+		return err
+	}
+	move := q.WithContext(ctx).Where(q.UID.Eq(sourceUID))
+	if len(roleIDs) > 0 {
+		move = move.Where(q.RoleID.NotIn(roleIDs...))
+	}
+	if _, err := move.Update(q.UID, targetUID); err != nil {
+		return err
+	}
+	_, err := q.WithContext(ctx).Unscoped().Where(q.UID.Eq(sourceUID)).Delete()
+	return err
+}
+
+// moveReferrals source邀请的用户改为由target邀请，source的被邀请记录在target没有时转移
+// source和target之间的邀请关系合并后会变成自己邀请自己，直接删除
+func (u *UserService) moveReferrals(ctx context.Context, tx *query.Query, sourceUID, targetUID int64) error {
+	q := tx.UserReferral
+	if _, err := q.WithContext(ctx).Unscoped().Where(q.UID.Eq(sourceUID), q.ReferrerUID.Eq(targetUID)).Delete(); err != nil {
+		return err
+	}
+	if _, err := q.WithContext(ctx).Unscoped().Where(q.UID.Eq(targetUID), q.ReferrerUID.Eq(sourceUID)).Delete(); err != nil {
+		return err
+	}
+	if _, err := q.WithContext(ctx).Where(q.ReferrerUID.Eq(sourceUID)).Update(q.ReferrerUID, targetUID); err != nil {
+		return err
+	}
+	// uid上有唯一索引，target已有被邀请记录时保留target的
+	count, err := q.WithContext(ctx).Unscoped().Where(q.UID.Eq(targetUID)).Count()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		_, err = q.WithContext(ctx).Where(q.UID.Eq(sourceUID)).Update(q.UID, targetUID)
+		return err
+	}
+	_, err = q.WithContext(ctx).Unscoped().Where(q.UID.Eq(sourceUID)).Delete()
+	return err
+}
+
+// moveCredentials 手机号和邮箱在租户内唯一，先把source改为占位值再写入target
+func (u *UserService) moveCredentials(ctx context.Context, tx *query.Query, source, target *model.UserAccount) error {
+	accountQ := tx.UserAccount
+	movePhone := source.PhoneVerified && !target.PhoneVerified
+	moveEmail := source.EmailVerified && !target.EmailVerified
+	movePassword := source.Password != nil && target.Password == nil
+	if !movePhone && !moveEmail && !movePassword {
+		return nil
+	}
+	if movePhone || moveEmail {
+		if _, err := accountQ.WithContext(ctx).Where(accountQ.ID.Eq(source.ID)).UpdateSimple(
+			accountQ.PhoneCountryCode.Value(""),
+			accountQ.Phone.Value(common.PlaceholderPhone(source.ID)),
+			accountQ.Email.Value(common.PlaceholderEmail(source.ID)),
+		); err != nil {
+			return err
+		}
+	}
+	updates := &model.UserAccount{}
+	columns := make([]field.Expr, 0, 7)
+	if movePhone {
+		updates.PhoneCountryCode = source.PhoneCountryCode
+		updates.Phone = source.Phone
+		updates.PhoneVerified = true
+		columns = append(columns, accountQ.PhoneCountryCode, accountQ.Phone, accountQ.PhoneVerified)
+	}
+	if moveEmail {
+		updates.Email = source.Email
+		updates.EmailVerified = true
+		columns = append(columns, accountQ.Email, accountQ.EmailVerified)
+	}
+	if movePassword {
+		updates.Password = source.Password
+		updates.PasswordUpdatedAt = source.PasswordUpdatedAt
+		columns = append(columns, accountQ.Password, accountQ.PasswordUpdatedAt)
+	}
+	_, err := accountQ.WithContext(ctx).Where(accountQ.ID.Eq(target.ID)).Select(columns...).Updates(updates)
+	return err
+}
@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/byteflowing/base/app/user/common"
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/ecode"
+	"github.com/byteflowing/base/pkg/logx"
+	"github.com/byteflowing/base/pkg/totp"
+	"github.com/byteflowing/base/pkg/utils/crypto"
+	"github.com/byteflowing/base/pkg/utils/trans"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+const (
+	mfaTriesKeyFormat = "%s:mfa_tries:%s" // prefix:jti
+
+	defaultRecoveryCodes = 10
+)
+
+// EnrollTotp 发起TOTP绑定，返回密钥及otpauth uri，密钥加密后保存
+// 此时两步验证尚未生效，需要用户在身份验证器中添加后调用ConfirmTotp确认
+func (u *UserService) EnrollTotp(ctx context.Context, req *userv1.EnrollTotpReq) (*userv1.EnrollTotpResp, error) {
+	if u.totp == nil {
+		return nil, ecode.ErrUserMfaUnsupported
+	}
+	uid, _, err := u.parseSensitiveAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	userAccount, err := u.getUserAccount(ctx, u.db, uid)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := u.encryptSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	err = u.db.Transaction(func(tx *query.Query) error {
+		q := tx.UserTotp
+		m, err := q.WithContext(ctx).Where(q.UID.Eq(uid)).Take()
+		if err == nil {
+			if m.Status == int16(enumsv1.MfaStatus_MFA_STATUS_ENABLED) {
+				return ecode.ErrUserMfaAlreadyEnabled
+			}
+			_, err = q.WithContext(ctx).Where(q.ID.Eq(m.ID)).Update(q.Secret, encrypted)
+			return err
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return q.WithContext(ctx).Create(&model.UserTotp{
+			TenantID: userAccount.TenantID,
+			UID:      uid,
+			Status:   int16(enumsv1.MfaStatus_MFA_STATUS_PENDING),
+			Secret:   encrypted,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.EnrollTotpResp{
+		Secret: secret,
+		Uri:    u.totp.URI(u.cfg.Mfa.Issuer, userAccount.Number, secret),
+	}, nil
+}
+
+// ConfirmTotp 使用身份验证器生成的验证码确认绑定，成功后生成恢复码
+// 恢复码只在此时返回一次，服务端仅保存哈希值
+func (u *UserService) ConfirmTotp(ctx context.Context, req *userv1.ConfirmTotpReq) (*userv1.ConfirmTotpResp, error) {
+	if u.totp == nil {
+		return nil, ecode.ErrUserMfaUnsupported
+	}
+	uid, _, err := u.parseSensitiveAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	var codes []string
+	err = u.db.Transaction(func(tx *query.Query) error {
+		q := tx.UserTotp
+		m, err := q.WithContext(ctx).Where(q.UID.Eq(uid)).Take()
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ecode.ErrUserMfaNotEnrolled
+			}
+			return err
+		}
+		if m.Status == int16(enumsv1.MfaStatus_MFA_STATUS_ENABLED) {
+			return ecode.ErrUserMfaAlreadyEnabled
+		}
+		secret, err := u.decryptSecret(m.Secret)
+		if err != nil {
+			return err
+		}
+		step, err := u.totp.Validate(secret, req.Code, time.Now())
+		if err != nil {
+			return ecode.ErrUserMfaCodeInvalid
+		}
+		if _, err := q.WithContext(ctx).Where(q.ID.Eq(m.ID)).Updates(&model.UserTotp{
+			Status:    int16(enumsv1.MfaStatus_MFA_STATUS_ENABLED),
+			LastStep:  int64(step),
+			EnabledAt: trans.Ref(time.Now()),
+		}); err != nil {
+			return err
+		}
+		codes, err = u.resetRecoveryCodes(ctx, tx, uid)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.ConfirmTotpResp{RecoveryCodes: codes}, nil
+}
+
+// DisableTotp 关闭两步验证，需要提供当前的验证码或者恢复码
+func (u *UserService) DisableTotp(ctx context.Context, req *userv1.DisableTotpReq) (*userv1.DisableTotpResp, error) {
+	if u.totp == nil {
+		return nil, ecode.ErrUserMfaUnsupported
+	}
+	uid, _, err := u.parseSensitiveAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	err = u.db.Transaction(func(tx *query.Query) error {
+		if err := u.verifyMfaCode(ctx, tx, uid, req.Code, req.RecoveryCode); err != nil {
+			return err
+		}
+		q := tx.UserTotp
+		if _, err := q.WithContext(ctx).Unscoped().Where(q.UID.Eq(uid)).Delete(); err != nil {
+			return err
+		}
+		codeQ := tx.UserRecoveryCode
+		_, err := codeQ.WithContext(ctx).Unscoped().Where(codeQ.UID.Eq(uid)).Delete()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.DisableTotpResp{}, nil
+}
+
+// VerifySignInMfa 登录第二步，校验SignIn返回的mfa token及验证码后签发正式的access/refresh token
+func (u *UserService) VerifySignInMfa(ctx context.Context, req *userv1.VerifySignInMfaReq) (*userv1.VerifySignInMfaResp, error) {
+	if u.totp == nil {
+		return nil, ecode.ErrUserMfaUnsupported
+	}
+	claims, err := u.token.Parse(req.MfaToken, enumsv1.TokenType_TOKEN_TYPE_MFA.String())
+	if err != nil {
+		return nil, err
+	}
+	jti := common.GetJwtJti(claims)
+	if jti == "" {
+		return nil, ecode.ErrUserTokenInvalid
+	}
+	blocked, err := u.blk.Exists(ctx, jti)
+	if err != nil {
+		return nil, err
+	}
+	if blocked {
+		return nil, ecode.ErrUserTokenInvalid
+	}
+	if err := u.checkMfaTries(ctx, jti); err != nil {
+		return nil, err
+	}
+	uid, err := common.GetJwtUID(claims)
+	if err != nil {
+		return nil, ecode.ErrUserTokenInvalid
+	}
+	signInType, identifier := common.GetMfaSignInInfo(claims)
+	var resp *userv1.SignInResp
+	var alert *deviceAlert
+	err = u.db.Transaction(func(tx *query.Query) error {
+		userAccount, err := u.getUserAccount(ctx, tx, uid)
+		if err != nil {
+			return err
+		}
+		if !common.IsUserValid(userAccount.Status) {
+			return ecode.ErrUserDisabled
+		}
+		if err := u.verifyMfaCode(ctx, tx, uid, req.Code, req.RecoveryCode); err != nil {
+			return err
+		}
+		user := common.UserModelToUser(userAccount)
+		resp, alert, err = u.issueSignIn(ctx, tx, user, signInType, identifier, req.Agent, req.ExtraJwtClaims, true)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.sendDeviceAlert(ctx, resp.UserInfo, alert)
+	// mfa token只能使用一次，登录已经成功，加入黑名单失败时只记录日志
+	if err := u.addJtiToBlkByClaims(ctx, claims); err != nil {
+		logx.CtxError(ctx, "block mfa token failed", zap.Int64("uid", uid), zap.Error(err))
+	}
+	return &userv1.VerifySignInMfaResp{
+		AccessToken:  resp.AccessToken,
+		RefreshToken: resp.RefreshToken,
+		UserInfo:     resp.UserInfo,
+	}, nil
+}
+
+// checkMfa 用户开启了两步验证时返回mfa token，否则返回空字符串
+func (u *UserService) checkMfa(ctx context.Context, tx *query.Query, result *userv1.SignInResult, signInType enumsv1.SignInType) (string, error) {
+	// 登录方式要求二次验证但用户没有开启两步验证时拒绝登录
+	if u.totp == nil {
+		if result.StepUp {
+			return "", ecode.ErrUserSignInRisky
+		}
+		return "", nil
+	}
+	user := result.User
+	q := tx.UserTotp
+	_, err := q.WithContext(ctx).Where(
+		q.UID.Eq(user.GetUid()),
+		q.Status.Eq(int16(enumsv1.MfaStatus_MFA_STATUS_ENABLED)),
+	).Take()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if result.StepUp {
+				return "", ecode.ErrUserSignInRisky
+			}
+			return "", nil
+		}
+		return "", err
+	}
+	token, err := u.token.Generate(
+		strconv.FormatInt(user.GetUid(), 10),
+		enumsv1.TokenType_TOKEN_TYPE_MFA.String(),
+		u.cfg.Mfa.PendingTtl.AsDuration(),
+		map[string]any{
+			common.JwtTenantIDKey:   user.GetTenantId(),
+			common.JwtSignInTypeKey: signInType.String(),
+			common.JwtIdentifierKey: result.Identifier,
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	return token.Token, nil
+}
+
+// verifyMfaCode 校验TOTP验证码或者恢复码，二者传其一即可
+// TOTP验证码在同一时间步内只能使用一次，恢复码使用后即作废
+func (u *UserService) verifyMfaCode(ctx context.Context, tx *query.Query, uid int64, code, recoveryCode string) error {
+	if code == "" && recoveryCode == "" {
+		return ecode.ErrParams
+	}
+	q := tx.UserTotp
+	m, err := q.WithContext(ctx).Where(
+		q.UID.Eq(uid),
+		q.Status.Eq(int16(enumsv1.MfaStatus_MFA_STATUS_ENABLED)),
+	).Take()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ecode.ErrUserMfaNotEnabled
+		}
+		return err
+	}
+	if code != "" {
+		secret, err := u.decryptSecret(m.Secret)
+		if err != nil {
+			return err
+		}
+		step, err := u.totp.Validate(secret, code, time.Now())
+		if err != nil {
+			return ecode.ErrUserMfaCodeInvalid
+		}
+		info, err := q.WithContext(ctx).Where(q.ID.Eq(m.ID), q.LastStep.Lt(int64(step))).Update(q.LastStep, int64(step))
+		if err != nil {
+			return err
+		}
+		if info.RowsAffected == 0 {
+			return ecode.ErrUserMfaCodeInvalid
+		}
+		return nil
+	}
+	codeQ := tx.UserRecoveryCode
+	codes, err := codeQ.WithContext(ctx).Where(codeQ.UID.Eq(uid), codeQ.UsedAt.IsNull()).Find()
+	if err != nil {
+		return err
+	}
+	recoveryCode = totp.NormalizeRecoveryCode(recoveryCode)
+	for _, c := range codes {
+		ok, err := crypto.DefaultPasswordHasher.VerifyPassword(recoveryCode, c.CodeHash)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		info, err := codeQ.WithContext(ctx).Where(codeQ.ID.Eq(c.ID), codeQ.UsedAt.IsNull()).Update(codeQ.UsedAt, time.Now())
+		if err != nil {
+			return err
+		}
+		if info.RowsAffected == 0 {
+			return ecode.ErrUserMfaCodeInvalid
+		}
+		return nil
+	}
+	return ecode.ErrUserMfaCodeInvalid
+}
+
+func (u *UserService) resetRecoveryCodes(ctx context.Context, tx *query.Query, uid int64) ([]string, error) {
+	count := int(u.cfg.Mfa.RecoveryCodes)
+	if count <= 0 {
+		count = defaultRecoveryCodes
+	}
+	codes, err := totp.GenerateRecoveryCodes(count)
+	if err != nil {
+		return nil, err
+	}
+	models := make([]*model.UserRecoveryCode, 0, count)
+	for _, code := range codes {
+		hash, err := crypto.DefaultPasswordHasher.HashPassword(code)
+		if err != nil {
+			return nil, err
+		}
+		models = append(models, &model.UserRecoveryCode{
+			UID:      uid,
+			CodeHash: hash,
+		})
+	}
+	q := tx.UserRecoveryCode
+	if _, err := q.WithContext(ctx).Unscoped().Where(q.UID.Eq(uid)).Delete(); err != nil {
+		return nil, err
+	}
+	if err := q.WithContext(ctx).Create(models...); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// checkMfaTries 限制同一个mfa token的验证次数，超过次数后吊销该token
+func (u *UserService) checkMfaTries(ctx context.Context, jti string) error {
+	maxTries := int64(u.cfg.Mfa.MaxTries)
+	if maxTries <= 0 {
+		return nil
+	}
+	ttl := u.cfg.Mfa.PendingTtl.AsDuration()
+	tries, err := u.incrTries(ctx, fmt.Sprintf(mfaTriesKeyFormat, u.cfg.KeyPrefix, jti), ttl)
+	if err != nil {
+		return err
+	}
+	if tries > maxTries {
+		if err := u.blk.Add(ctx, jti, ttl); err != nil {
+			return err
+		}
+		return ecode.ErrUserTokenInvalid
+	}
+	return nil
+}
+
+// incrTries 增加尝试次数并返回当前次数，计数在ttl后过期
+func (u *UserService) incrTries(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	pipe := u.rdb.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/byteflowing/base/app/user/common"
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/ecode"
+	"github.com/byteflowing/base/pkg/password"
+	"github.com/byteflowing/base/pkg/utils/crypto"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+// ChangePassword 修改密码，已设置密码时需要提供原密码
+// 新密码需要满足租户的密码策略，并且不能和最近使用过的密码相同，修改成功后吊销当前会话以外的所有会话
+func (u *UserService) ChangePassword(ctx context.Context, req *userv1.ChangePasswordReq) (*userv1.ChangePasswordResp, error) {
+	uid, claims, err := u.parseSensitiveAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	rv := &revocation{}
+	err = u.db.Transaction(func(tx *query.Query) error {
+		userAccount, err := u.getUserAccount(ctx, tx, uid)
+		if err != nil {
+			return err
+		}
+		if !common.IsUserValid(userAccount.Status) {
+			return ecode.ErrUserDisabled
+		}
+		if userAccount.Source == int16(enumsv1.UserSource_USER_SOURCE_GUEST) {
+			return ecode.ErrUserGuestNotAllowed
+		}
+		if userAccount.Password != nil {
+			if req.OldPassword == "" {
+				return ecode.ErrUserPasswordInvalid
+			}
+			ok, err := crypto.DefaultPasswordHasher.VerifyPassword(req.OldPassword, *userAccount.Password)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return ecode.ErrUserPasswordInvalid
+			}
+		}
+		return u.setPassword(ctx, tx, rv, userAccount, req.NewPassword, common.GetJwtJti(claims))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := u.addBlockItems(ctx, rv.items); err != nil {
+		return nil, err
+	}
+	return &userv1.ChangePasswordResp{}, nil
+}
+
+// setPassword 按租户的密码策略校验并保存新密码，记录密码历史
+// 旧密码可能已经泄露，吊销access token为keepAccessJti的会话以外的所有会话
+func (u *UserService) setPassword(ctx context.Context, tx *query.Query, rv *revocation, userAccount *model.UserAccount, newPassword, keepAccessJti string) error {
+	tenant, err := u.getTenant(ctx, tx, userAccount.TenantID)
+	if err != nil {
+		return err
+	}
+	policy := tenant.passwordPolicy
+	if err := validatePassword(policy, newPassword); err != nil {
+		return err
+	}
+	if err := u.checkPasswordHistory(ctx, tx, userAccount, policy, newPassword); err != nil {
+		return err
+	}
+	hash, err := crypto.DefaultPasswordHasher.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	accountQ := tx.UserAccount
+	if _, err := accountQ.WithContext(ctx).Where(accountQ.ID.Eq(userAccount.ID)).UpdateSimple(
+		accountQ.Password.Value(hash),
+		accountQ.PasswordUpdatedAt.Value(now),
+	); err != nil {
+		return err
+	}
+	if err := u.recordPasswordHistory(ctx, tx, userAccount, policy, hash); err != nil {
+		return err
+	}
+	if err := u.revokeOtherSessions(ctx, tx, rv, userAccount.ID, enumsv1.SignInStatus_SIGN_IN_STATUS_REVOKED, keepAccessJti); err != nil {
+		return err
+	}
+	return u.emitEvent(ctx, tx, enumsv1.UserEventType_USER_EVENT_TYPE_PASSWORD_CHANGED, userAccount.TenantID, userAccount.ID, nil)
+}
+
+// checkPasswordHistory 新密码不能和当前密码以及最近history_size个历史密码相同
+func (u *UserService) checkPasswordHistory(ctx context.Context, tx *query.Query, userAccount *model.UserAccount, policy *userv1.PasswordPolicy, newPassword string) error {
+	size := int(policy.GetHistorySize())
+	if size <= 0 {
+		return nil
+	}
+	var hashes []string
+	if userAccount.Password != nil {
+		hashes = append(hashes, *userAccount.Password)
+	}
+	q := tx.UserPasswordHistory
+	var history []string
+	if err := q.WithContext(ctx).Where(q.UID.Eq(userAccount.ID)).Order(q.ID.Desc()).Limit(size).Pluck(q.Hash, &history); err != nil {
+		return err
+	}
+	hashes = append(hashes, history...)
+	for _, hash := range hashes {
+		ok, err := crypto.DefaultPasswordHasher.VerifyPassword(newPassword, hash)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return ecode.ErrUserPasswordReused
+		}
+	}
+	return nil
+}
+
+// recordPasswordHistory 保存新密码的哈希，只保留最近history_size条
+func (u *UserService) recordPasswordHistory(ctx context.Context, tx *query.Query, userAccount *model.UserAccount, policy *userv1.PasswordPolicy, hash string) error {
+	size := int(policy.GetHistorySize())
+	q := tx.UserPasswordHistory
+	if size <= 0 {
+		_, err := q.WithContext(ctx).Unscoped().Where(q.UID.Eq(userAccount.ID)).Delete()
+		return err
+	}
+	if err := q.WithContext(ctx).Create(&model.UserPasswordHistory{
+		TenantID: userAccount.TenantID,
+		UID:      userAccount.ID,
+		Hash:     hash,
+	}); err != nil {
+		return err
+	}
+	var expired []int64
+	if err := q.WithContext(ctx).Where(q.UID.Eq(userAccount.ID)).Order(q.ID.Desc()).Offset(size).Pluck(q.ID, &expired); err != nil {
+		return err
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+	_, err := q.WithContext(ctx).Unscoped().Where(q.ID.In(expired...)).Delete()
+	return err
+}
+
+// mustChangePassword 密码超过策略中的最长使用时间后，登录时提示用户修改密码
+// 没有记录修改时间的密码从注册时间开始计算
+func (u *UserService) mustChangePassword(ctx context.Context, tx *query.Query, user *userv1.User) (bool, error) {
+	tenant, err := u.getTenant(ctx, tx, user.GetTenantId())
+	if err != nil {
+		return false, err
+	}
+	maxAge := tenant.passwordPolicy.GetMaxAge().AsDuration()
+	if maxAge <= 0 {
+		return false, nil
+	}
+	userAccount, err := u.getUserAccount(ctx, tx, user.GetUid())
+	if err != nil {
+		return false, err
+	}
+	if userAccount.Password == nil {
+		return false, nil
+	}
+	updatedAt := userAccount.PasswordUpdatedAt
+	if updatedAt == nil {
+		updatedAt = userAccount.CreatedAt
+	}
+	return updatedAt != nil && time.Since(*updatedAt) > maxAge, nil
+}
+
+func validatePassword(policy *userv1.PasswordPolicy, pw string) error {
+	if pw == "" {
+		return ecode.ErrParams
+	}
+	p := &password.Policy{
+		MinLength:     int(policy.GetMinLength()),
+		RequireUpper:  policy.GetRequireUpper(),
+		RequireLower:  policy.GetRequireLower(),
+		RequireDigit:  policy.GetRequireDigit(),
+		RequireSymbol: policy.GetRequireSymbol(),
+		RejectCommon:  policy.GetRejectCommon(),
+	}
+	switch err := p.Validate(pw); {
+	case err == nil:
+		return nil
+	case errors.Is(err, password.ErrTooShort):
+		return ecode.ErrUserPasswordTooShort
+	case errors.Is(err, password.ErrCommon):
+		return ecode.ErrUserPasswordCommon
+	default:
+		return ecode.ErrUserPasswordTooSimple
+	}
+}
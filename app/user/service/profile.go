@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/byteflowing/base/app/user/common"
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/ecode"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	geov1 "github.com/byteflowing/proto/gen/go/geo/v1"
+	typesv1 "github.com/byteflowing/proto/gen/go/types/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+	"google.golang.org/genproto/googleapis/type/date"
+)
+
+const (
+	maxBatchGetUsers = 100
+)
+
+func (u *UserService) GetUser(ctx context.Context, req *userv1.GetUserReq) (*userv1.GetUserResp, error) {
+	userAccount, err := u.getUserAccount(ctx, u.db, req.Uid)
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.GetUserResp{User: common.UserModelToUser(userAccount)}, nil
+}
+
+func (u *UserService) BatchGetUsers(ctx context.Context, req *userv1.BatchGetUsersReq) (*userv1.BatchGetUsersResp, error) {
+	if len(req.Uids) == 0 {
+		return &userv1.BatchGetUsersResp{}, nil
+	}
+	if len(req.Uids) > maxBatchGetUsers {
+		return nil, ecode.ErrParams
+	}
+	q := u.db.UserAccount
+	accounts, err := q.WithContext(ctx).Where(q.ID.In(req.Uids...)).Find()
+	if err != nil {
+		return nil, err
+	}
+	users := make([]*userv1.User, 0, len(accounts))
+	for _, account := range accounts {
+		users = append(users, common.UserModelToUser(account))
+	}
+	return &userv1.BatchGetUsersResp{Users: users}, nil
+}
+
+// UpdateProfile 用户修改自己的资料，只更新请求中设置了的字段
+func (u *UserService) UpdateProfile(ctx context.Context, req *userv1.UpdateProfileReq) (*userv1.UpdateProfileResp, error) {
+	uid, _, err := u.parseAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	userAccount, err := u.getUserAccount(ctx, u.db, uid)
+	if err != nil {
+		return nil, err
+	}
+	if !common.IsUserValid(userAccount.Status) {
+		return nil, ecode.ErrUserDisabled
+	}
+	updates := &model.UserAccount{}
+	// fields 记录修改的字段，随profile_updated事件发布
+	var fields []string
+	if req.Name != nil {
+		updates.Name = req.Name
+		fields = append(fields, "name")
+	}
+	if req.Alias != nil {
+		updates.Alias_ = req.Alias
+		fields = append(fields, "alias")
+	}
+	if req.Avatar != nil {
+		updates.Avatar = req.Avatar
+		fields = append(fields, "avatar")
+	}
+	if req.Gender != nil {
+		gender := int16(req.GetGender())
+		updates.Gender = &gender
+		fields = append(fields, "gender")
+	}
+	if req.Birthday != nil {
+		birthday, err := u.parseBirthday(req.Birthday)
+		if err != nil {
+			return nil, err
+		}
+		updates.Birthday = &birthday
+		fields = append(fields, "birthday")
+	}
+	if req.Region != nil {
+		if err := u.checkRegion(ctx, req.Region); err != nil {
+			return nil, err
+		}
+		updates.CountryCode = req.Region.CountryCode
+		updates.ProvinceCode = req.Region.ProvinceCode
+		updates.CityCode = req.Region.CityCode
+		updates.DistrictCode = req.Region.DistrictCode
+		fields = append(fields, "region")
+	}
+	if req.Addr != nil {
+		updates.Addr = req.Addr
+		fields = append(fields, "addr")
+	}
+	if req.Ext != nil {
+		if !json.Valid([]byte(req.GetExt())) {
+			return nil, ecode.ErrUserExtInvalid
+		}
+		updates.Ext = req.Ext
+		fields = append(fields, "ext")
+	}
+	err = u.db.Transaction(func(tx *query.Query) error {
+		q := tx.UserAccount
+		if _, err := q.WithContext(ctx).Where(q.ID.Eq(uid)).Updates(updates); err != nil {
+			return err
+		}
+		userAccount, err = u.getUserAccount(ctx, tx, uid)
+		if err != nil {
+			return err
+		}
+		if len(fields) == 0 {
+			return nil
+		}
+		return u.emitEvent(ctx, tx, enumsv1.UserEventType_USER_EVENT_TYPE_PROFILE_UPDATED, userAccount.TenantID, uid, &userv1.UserProfileUpdatedEvent{
+			Fields: fields,
+			User:   common.UserModelToUser(userAccount),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.UpdateProfileResp{User: common.UserModelToUser(userAccount)}, nil
+}
+
+func (u *UserService) parseBirthday(d *date.Date) (time.Time, error) {
+	birthday := time.Date(int(d.Year), time.Month(d.Month), int(d.Day), 0, 0, 0, 0, time.UTC)
+	if birthday.Year() != int(d.Year) || birthday.Month() != time.Month(d.Month) || birthday.Day() != int(d.Day) {
+		return time.Time{}, ecode.ErrParams
+	}
+	if birthday.After(time.Now()) {
+		return time.Time{}, ecode.ErrParams
+	}
+	return birthday, nil
+}
+
+// checkRegion 使用geo服务校验地区编码是否存在
+func (u *UserService) checkRegion(ctx context.Context, region *typesv1.AdminRegion) error {
+	if u.geo == nil {
+		return ecode.ErrUnImplemented
+	}
+	res, err := u.geo.CheckGeoRegion(ctx, &geov1.CheckGeoRegionReq{
+		CountryCca2:  region.CountryCode,
+		ProvinceCode: region.ProvinceCode,
+		CityCode:     region.CityCode,
+		DistrictCode: region.DistrictCode,
+	})
+	if err != nil {
+		return err
+	}
+	if !res.Ok {
+		return ecode.ErrUserRegionInvalid
+	}
+	return nil
+}
@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/app/user/rbac"
+	"github.com/byteflowing/base/ecode"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+// token中的roles在用户下次签发token时更新，CheckPermission实时查询用户绑定的角色和权限，修改立即生效
+
+func (u *UserService) CreateRole(ctx context.Context, req *userv1.CreateRoleReq) (*userv1.CreateRoleResp, error) {
+	if req.Name == "" {
+		return nil, ecode.ErrParams
+	}
+	if req.TenantId != "" {
+		if _, err := u.getTenantModel(ctx, req.TenantId); err != nil {
+			return nil, err
+		}
+	}
+	var role *userv1.Role
+	err := u.db.Transaction(func(tx *query.Query) error {
+		q := tx.UserRole
+		_, err := q.WithContext(ctx).Where(q.TenantID.Eq(req.TenantId), q.Name.Eq(req.Name)).Take()
+		if err == nil {
+			return ecode.ErrUserRoleExists
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		m := &model.UserRole{
+			TenantID:    req.TenantId,
+			Name:        req.Name,
+			Description: req.Description,
+		}
+		if err := q.WithContext(ctx).Create(m); err != nil {
+			return err
+		}
+		if err := u.setRolePermissions(ctx, tx, m.ID, req.Permissions); err != nil {
+			return err
+		}
+		role, err = u.roleModelToRole(ctx, tx, m)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.CreateRoleResp{Role: role}, nil
+}
+
+// UpdateRole 修改角色描述，permissions会整体覆盖
+func (u *UserService) UpdateRole(ctx context.Context, req *userv1.UpdateRoleReq) (*userv1.UpdateRoleResp, error) {
+	var role *userv1.Role
+	err := u.db.Transaction(func(tx *query.Query) error {
+		m, err := u.getRole(ctx, tx, req.RoleId)
+		if err != nil {
+			return err
+		}
+		if req.Description != nil {
+			q := tx.UserRole
+			if _, err := q.WithContext(ctx).Where(q.ID.Eq(m.ID)).Update(q.Description, req.GetDescription()); err != nil {
+				return err
+			}
+			m.Description = req.Description
+		}
+		if err := u.setRolePermissions(ctx, tx, m.ID, req.Permissions); err != nil {
+			return err
+		}
+		role, err = u.roleModelToRole(ctx, tx, m)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.UpdateRoleResp{Role: role}, nil
+}
+
+// DeleteRole 删除角色，同时删除角色的权限以及用户和角色的绑定关系
+func (u *UserService) DeleteRole(ctx context.Context, req *userv1.DeleteRoleReq) (*userv1.DeleteRoleResp, error) {
+	err := u.db.Transaction(func(tx *query.Query) error {
+		m, err := u.getRole(ctx, tx, req.RoleId)
+		if err != nil {
+			return err
+		}
+		bindingQ := tx.UserRoleBinding
+		if _, err := bindingQ.WithContext(ctx).Unscoped().Where(bindingQ.RoleID.Eq(m.ID)).Delete(); err != nil {
+			return err
+		}
+		permQ := tx.UserRolePermission
+		if _, err := permQ.WithContext(ctx).Unscoped().Where(permQ.RoleID.Eq(m.ID)).Delete(); err != nil {
+			return err
+		}
+		q := tx.UserRole
+		_, err = q.WithContext(ctx).Unscoped().Where(q.ID.Eq(m.ID)).Delete()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.DeleteRoleResp{}, nil
+}
+
+func (u *UserService) ListRoles(ctx context.Context, req *userv1.ListRolesReq) (*userv1.ListRolesResp, error) {
+	q := u.db.UserRole
+	models, err := q.WithContext(ctx).Where(q.TenantID.Eq(req.TenantId)).Order(q.ID).Find()
+	if err != nil {
+		return nil, err
+	}
+	roles, err := u.roleModelsToRoles(ctx, u.db, models)
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.ListRolesResp{Roles: roles}, nil
+}
+
+// AssignUserRoles 给用户分配角色，角色必须和用户属于同一租户，已分配的角色会被忽略
+func (u *UserService) AssignUserRoles(ctx context.Context, req *userv1.AssignUserRolesReq) (*userv1.AssignUserRolesResp, error) {
+	if len(req.RoleIds) == 0 {
+		return &userv1.AssignUserRolesResp{}, nil
+	}
+	// 重复的角色id只查询一次，否则查询到的角色数量和请求不一致
+	roleIDs := slices.Compact(slices.Sorted(slices.Values(req.RoleIds)))
+	err := u.db.Transaction(func(tx *query.Query) error {
+		userAccount, err := u.getUserAccount(ctx, tx, req.Uid)
+		if err != nil {
+			return err
+		}
+		roleQ := tx.UserRole
+		roles, err := roleQ.WithContext(ctx).Where(
+			roleQ.ID.In(roleIDs...),
+			roleQ.TenantID.Eq(userAccount.TenantID),
+		).Find()
+		if err != nil {
+			return err
+		}
+		if len(roles) != len(roleIDs) {
+			return ecode.ErrUserRoleNotFound
+		}
+		bindingQ := tx.UserRoleBinding
+		bindings, err := bindingQ.WithContext(ctx).Where(
+			bindingQ.UID.Eq(req.Uid),
+			bindingQ.RoleID.In(roleIDs...),
+		).Find()
+		if err != nil {
+			return err
+		}
+		assigned := make(map[int64]struct{}, len(bindings))
+		for _, b := range bindings {
+			assigned[b.RoleID] = struct{}{}
+		}
+		var creates []*model.UserRoleBinding
+		for _, role := range roles {
+			if _, ok := assigned[role.ID]; ok {
+				continue
+			}
+			creates = append(creates, &model.UserRoleBinding{
+				TenantID: userAccount.TenantID,
+				UID:      req.Uid,
+				RoleID:   role.ID,
+			})
+		}
+		if len(creates) == 0 {
+			return nil
+		}
+		return bindingQ.WithContext(ctx).Create(creates...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.AssignUserRolesResp{}, nil
+}
+
+func (u *UserService) RemoveUserRoles(ctx context.Context, req *userv1.RemoveUserRolesReq) (*userv1.RemoveUserRolesResp, error) {
+	if len(req.RoleIds) == 0 {
+		return &userv1.RemoveUserRolesResp{}, nil
+	}
+	q := u.db.UserRoleBinding
+	if _, err := q.WithContext(ctx).Unscoped().Where(
+		q.UID.Eq(req.Uid),
+		q.RoleID.In(req.RoleIds...),
+	).Delete(); err != nil {
+		return nil, err
+	}
+	return &userv1.RemoveUserRolesResp{}, nil
+}
+
+func (u *UserService) GetUserRoles(ctx context.Context, req *userv1.GetUserRolesReq) (*userv1.GetUserRolesResp, error) {
+	models, err := u.getUserRoles(ctx, u.db, req.Uid)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := u.roleModelsToRoles(ctx, u.db, models)
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.GetUserRolesResp{Roles: roles}, nil
+}
+
+// CheckPermission 判断用户在租户下绑定的角色是否拥有指定权限
+// 角色从user_role_binding实时查询，不使用token中的roles，避免token中的租户和角色被伪造
+func (u *UserService) CheckPermission(ctx context.Context, req *userv1.CheckPermissionReq) (*userv1.CheckPermissionResp, error) {
+	if req.Permission == "" || req.Uid == 0 {
+		return nil, ecode.ErrParams
+	}
+	allowed, err := u.hasPermission(ctx, u.db, req.TenantId, req.Uid, req.Permission)
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.CheckPermissionResp{Allowed: allowed}, nil
+}
+
+// hasPermission 用户在租户下绑定的角色是否授予了permission
+func (u *UserService) hasPermission(ctx context.Context, tx *query.Query, tenantID string, uid int64, permission string) (bool, error) {
+	roleQ := tx.UserRole
+	bindingQ := tx.UserRoleBinding
+	permQ := tx.UserRolePermission
+	var permissions []string
+	if err := permQ.WithContext(ctx).
+		Join(roleQ, roleQ.ID.EqCol(permQ.RoleID)).
+		Join(bindingQ, bindingQ.RoleID.EqCol(roleQ.ID)).
+		Where(
+			bindingQ.UID.Eq(uid),
+			bindingQ.TenantID.Eq(tenantID),
+			roleQ.TenantID.Eq(tenantID),
+		).
+		Pluck(permQ.Permission, &permissions); err != nil {
+		return false, err
+	}
+	for _, p := range permissions {
+		if rbac.MatchPermission(p, permission) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (u *UserService) getRole(ctx context.Context, tx *query.Query, roleID int64) (*model.UserRole, error) {
+	q := tx.UserRole
+	role, err := q.WithContext(ctx).Where(q.ID.Eq(roleID)).Take()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ecode.ErrUserRoleNotFound
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+func (u *UserService) getUserRoles(ctx context.Context, tx *query.Query, uid int64) ([]*model.UserRole, error) {
+	bindingQ := tx.UserRoleBinding
+	var roleIDs []int64
+	if err := bindingQ.WithContext(ctx).Where(bindingQ.UID.Eq(uid)).Pluck(bindingQ.RoleID, &roleIDs); err != nil {
+		return nil, err
+	}
+	if len(roleIDs) == 0 {
+		return nil, nil
+	}
+	roleQ := tx.UserRole
+	return roleQ.WithContext(ctx).Where(roleQ.ID.In(roleIDs...)).Order(roleQ.ID).Find()
+}
+
+// getUserRoleNames 获取用户的角色名，签发token时写入roles
+func (u *UserService) getUserRoleNames(ctx context.Context, tx *query.Query, uid int64) ([]string, error) {
+	roles, err := u.getUserRoles(ctx, tx, uid)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+	return names, nil
+}
+
+// setRolePermissions 使用permissions覆盖角色原有的权限
+func (u *UserService) setRolePermissions(ctx context.Context, tx *query.Query, roleID int64, permissions []string) error {
+	q := tx.UserRolePermission
+	if _, err := q.WithContext(ctx).Unscoped().Where(q.RoleID.Eq(roleID)).Delete(); err != nil {
+		return err
+	}
+	seen := make(map[string]struct{}, len(permissions))
+	creates := make([]*model.UserRolePermission, 0, len(permissions))
+	for _, p := range permissions {
+		if p == "" {
+			return ecode.ErrParams
+		}
+		if _, ok := seen[p]; ok {
+			continue
+		}
+		seen[p] = struct{}{}
+		creates = append(creates, &model.UserRolePermission{
+			RoleID:     roleID,
+			Permission: p,
+		})
+	}
+	if len(creates) == 0 {
+		return nil
+	}
+	return q.WithContext(ctx).Create(creates...)
+}
+
+func (u *UserService) roleModelToRole(ctx context.Context, tx *query.Query, m *model.UserRole) (*userv1.Role, error) {
+	roles, err := u.roleModelsToRoles(ctx, tx, []*model.UserRole{m})
+	if err != nil {
+		return nil, err
+	}
+	return roles[0], nil
+}
+
+func (u *UserService) roleModelsToRoles(ctx context.Context, tx *query.Query, models []*model.UserRole) ([]*userv1.Role, error) {
+	if len(models) == 0 {
+		return nil, nil
+	}
+	ids := make([]int64, 0, len(models))
+	for _, m := range models {
+		ids = append(ids, m.ID)
+	}
+	q := tx.UserRolePermission
+	perms, err := q.WithContext(ctx).Where(q.RoleID.In(ids...)).Order(q.ID).Find()
+	if err != nil {
+		return nil, err
+	}
+	permMap := make(map[int64][]string, len(models))
+	for _, p := range perms {
+		permMap[p.RoleID] = append(permMap[p.RoleID], p.Permission)
+	}
+	roles := make([]*userv1.Role, 0, len(models))
+	for _, m := range models {
+		role := &userv1.Role{
+			Id:          m.ID,
+			TenantId:    m.TenantID,
+			Name:        m.Name,
+			Description: m.Description,
+			Permissions: permMap[m.ID],
+		}
+		if m.UpdatedAt != nil {
+			role.UpdatedAt = timestamppb.New(*m.UpdatedAt)
+		}
+		if m.CreatedAt != nil {
+			role.CreatedAt = timestamppb.New(*m.CreatedAt)
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
@@ -0,0 +1,754 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+
+	"github.com/byteflowing/base/app/user/common"
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/ecode"
+	"github.com/byteflowing/base/pkg/scim"
+	"github.com/byteflowing/base/pkg/utils/crypto"
+	"github.com/byteflowing/base/pkg/utils/trans"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+// SCIM token格式为 scim_<prefix>_<secret>，和API key一样只保存secret的sha256
+// SCIM中的User对应user_account，通过user_scim_user记录userName和externalId，Group对应租户下的角色
+const (
+	scimTokenScheme = "scim"
+
+	scimDefaultCount = 100
+	scimMaxCount     = 200
+)
+
+// CreateScimToken 为租户创建SCIM token，完整的token只在这里返回一次
+func (u *UserService) CreateScimToken(ctx context.Context, req *userv1.CreateScimTokenReq) (*userv1.CreateScimTokenResp, error) {
+	if req.TenantId == "" || req.Name == "" {
+		return nil, ecode.ErrParams
+	}
+	if req.ExpiredAt != nil && !req.ExpiredAt.AsTime().After(time.Now()) {
+		return nil, ecode.ErrParams
+	}
+	if _, err := u.getTenantModel(ctx, req.TenantId); err != nil {
+		return nil, err
+	}
+	prefix, secret, err := newApiKey()
+	if err != nil {
+		return nil, err
+	}
+	m := &model.UserScimToken{
+		TenantID:   req.TenantId,
+		Name:       req.Name,
+		Prefix:     prefix,
+		SecretHash: crypto.Sha256Hex([]byte(secret)),
+		Status:     int16(enumsv1.ApiKeyStatus_API_KEY_STATUS_OK),
+	}
+	if req.ExpiredAt != nil {
+		expiredAt := req.ExpiredAt.AsTime()
+		m.ExpiredAt = &expiredAt
+	}
+	if err := u.db.UserScimToken.WithContext(ctx).Create(m); err != nil {
+		return nil, err
+	}
+	return &userv1.CreateScimTokenResp{
+		Token:     formatApiKey(scimTokenScheme, prefix, secret),
+		ScimToken: scimTokenModelToScimToken(m),
+	}, nil
+}
+
+// RevokeScimToken 吊销SCIM token，吊销后不能恢复
+func (u *UserService) RevokeScimToken(ctx context.Context, req *userv1.RevokeScimTokenReq) (*userv1.RevokeScimTokenResp, error) {
+	q := u.db.UserScimToken
+	if _, err := q.WithContext(ctx).Where(q.ID.Eq(req.Id)).Take(); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ecode.ErrUserScimTokenNotFound
+		}
+		return nil, err
+	}
+	if _, err := q.WithContext(ctx).Where(q.ID.Eq(req.Id)).Update(q.Status, int16(enumsv1.ApiKeyStatus_API_KEY_STATUS_REVOKED)); err != nil {
+		return nil, err
+	}
+	return &userv1.RevokeScimTokenResp{}, nil
+}
+
+func (u *UserService) ListScimTokens(ctx context.Context, req *userv1.ListScimTokensReq) (*userv1.ListScimTokensResp, error) {
+	q := u.db.UserScimToken
+	models, err := q.WithContext(ctx).Where(q.TenantID.Eq(req.TenantId)).Order(q.ID).Find()
+	if err != nil {
+		return nil, err
+	}
+	tokens := make([]*userv1.ScimToken, 0, len(models))
+	for _, m := range models {
+		tokens = append(tokens, scimTokenModelToScimToken(m))
+	}
+	return &userv1.ListScimTokensResp{Tokens: tokens}, nil
+}
+
+// AuthenticateScimToken 校验SCIM请求的bearer token，返回token所属的租户
+func (u *UserService) AuthenticateScimToken(ctx context.Context, token string) (string, error) {
+	prefix, secret, ok := parseApiKey(scimTokenScheme, token)
+	if !ok {
+		return "", ecode.ErrUserScimTokenInvalid
+	}
+	q := u.db.UserScimToken
+	m, err := q.WithContext(ctx).Where(q.Prefix.Eq(prefix)).Take()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ecode.ErrUserScimTokenInvalid
+		}
+		return "", err
+	}
+	hash := crypto.Sha256Hex([]byte(secret))
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(m.SecretHash)) != 1 {
+		return "", ecode.ErrUserScimTokenInvalid
+	}
+	now := time.Now()
+	if m.Status != int16(enumsv1.ApiKeyStatus_API_KEY_STATUS_OK) || (m.ExpiredAt != nil && !m.ExpiredAt.After(now)) {
+		return "", ecode.ErrUserScimTokenInvalid
+	}
+	if _, err := u.getTenant(ctx, u.db, m.TenantID); err != nil {
+		return "", err
+	}
+	if _, err := q.WithContext(ctx).Where(q.ID.Eq(m.ID)).UpdateSimple(q.LastUsedAt.Value(now)); err != nil {
+		return "", err
+	}
+	return m.TenantID, nil
+}
+
+func (u *UserService) ScimGetUser(ctx context.Context, tenantID, id string) (*scim.User, error) {
+	link, _, err := u.getScimUser(ctx, u.db, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	users, err := u.scimUsers(ctx, u.db, tenantID, []*model.UserScimUser{link})
+	if err != nil {
+		return nil, err
+	}
+	return users[0], nil
+}
+
+// ScimListUsers 支持按userName、externalId、emails.value和id过滤，startIndex从1开始
+func (u *UserService) ScimListUsers(ctx context.Context, tenantID, filter string, startIndex, count int) (*scim.ListResponse, error) {
+	conds, err := scim.ParseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	startIndex, count = scimPage(startIndex, count)
+	q := u.db.UserScimUser
+	where := []gen.Condition{q.TenantID.Eq(tenantID)}
+	for _, c := range conds {
+		switch c.Attr {
+		case "username":
+			where = append(where, q.UserName.Eq(c.Value))
+		case "externalid":
+			where = append(where, q.ExternalID.Eq(c.Value))
+		case "id":
+			uid, err := strconv.ParseInt(c.Value, 10, 64)
+			if err != nil {
+				return scim.NewListResponse(0, startIndex, nil), nil
+			}
+			where = append(where, q.UID.Eq(uid))
+		case "emails", "emails.value":
+			accountQ := u.db.UserAccount
+			var uids []int64
+			if err := accountQ.WithContext(ctx).Where(
+				accountQ.TenantID.Eq(tenantID),
+				accountQ.Email.Eq(common.NormalizeEmail(c.Value)),
+			).Pluck(accountQ.ID, &uids); err != nil {
+				return nil, err
+			}
+			if len(uids) == 0 {
+				return scim.NewListResponse(0, startIndex, nil), nil
+			}
+			where = append(where, q.UID.In(uids...))
+		default:
+			return nil, scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidFilter, "unsupported attribute "+c.Attr)
+		}
+	}
+	tx := q.WithContext(ctx).Where(where...)
+	total, err := tx.Count()
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 || total < int64(startIndex) {
+		return scim.NewListResponse(total, startIndex, nil), nil
+	}
+	links, err := tx.Order(q.ID).Offset(startIndex - 1).Limit(count).Find()
+	if err != nil {
+		return nil, err
+	}
+	users, err := u.scimUsers(ctx, u.db, tenantID, links)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]any, 0, len(users))
+	for _, user := range users {
+		resources = append(resources, user)
+	}
+	return scim.NewListResponse(total, startIndex, resources), nil
+}
+
+// ScimCreateUser 创建用户，租户下已有使用相同已验证邮箱且未关联SCIM的账号时直接关联该账号
+func (u *UserService) ScimCreateUser(ctx context.Context, tenantID string, in *scim.User) (*scim.User, error) {
+	attrs, err := scimUserToAttrs(in)
+	if err != nil {
+		return nil, err
+	}
+	var uid int64
+	rv := &revocation{}
+	err = u.db.Transaction(func(tx *query.Query) error {
+		if err := u.checkScimUserName(ctx, tx, tenantID, attrs.userName, 0); err != nil {
+			return err
+		}
+		userAccount, created, err := u.findOrCreateScimAccount(ctx, tx, tenantID, attrs)
+		if err != nil {
+			return err
+		}
+		uid = userAccount.ID
+		if err := tx.UserScimUser.WithContext(ctx).Create(&model.UserScimUser{
+			TenantID:   tenantID,
+			UID:        uid,
+			UserName:   attrs.userName,
+			ExternalID: attrs.externalID,
+		}); err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ecode.ErrUserScimUserExists
+			}
+			return err
+		}
+		if created {
+			if err := u.emitEvent(ctx, tx, enumsv1.UserEventType_USER_EVENT_TYPE_SIGNED_UP, tenantID, uid, &userv1.UserSignedUpEvent{
+				User: common.UserModelToUser(userAccount),
+			}); err != nil {
+				return err
+			}
+		}
+		return u.updateScimAccount(ctx, tx, rv, userAccount, attrs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := u.addBlockItems(ctx, rv.items); err != nil {
+		return nil, err
+	}
+	return u.ScimGetUser(ctx, tenantID, strconv.FormatInt(uid, 10))
+}
+
+// ScimReplaceUser PUT整体替换用户属性，没有传邮箱时保留原邮箱
+func (u *UserService) ScimReplaceUser(ctx context.Context, tenantID, id string, in *scim.User) (*scim.User, error) {
+	attrs, err := scimUserToAttrs(in)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.saveScimUser(ctx, tenantID, id, func(*scim.User) (*scimUserAttrs, error) {
+		return attrs, nil
+	}); err != nil {
+		return nil, err
+	}
+	return u.ScimGetUser(ctx, tenantID, id)
+}
+
+// ScimPatchUser 在当前属性上执行PATCH操作后保存，active设置为false时禁用账号并吊销会话
+func (u *UserService) ScimPatchUser(ctx context.Context, tenantID, id string, patch *scim.PatchOp) (*scim.User, error) {
+	if err := patch.Validate(); err != nil {
+		return nil, err
+	}
+	if err := u.saveScimUser(ctx, tenantID, id, func(current *scim.User) (*scimUserAttrs, error) {
+		if err := applyScimUserPatch(current, patch.Operations); err != nil {
+			return nil, err
+		}
+		return scimUserToAttrs(current)
+	}); err != nil {
+		return nil, err
+	}
+	return u.ScimGetUser(ctx, tenantID, id)
+}
+
+// ScimDeleteUser IdP删除用户时立即注销账号，不经过注销冷静期
+func (u *UserService) ScimDeleteUser(ctx context.Context, tenantID, id string) error {
+	rv := &revocation{}
+	err := u.db.Transaction(func(tx *query.Query) error {
+		_, userAccount, err := u.getScimUser(ctx, tx, tenantID, id)
+		if err != nil {
+			return err
+		}
+		return u.anonymizeAccount(ctx, tx, rv, userAccount.ID)
+	})
+	if err != nil {
+		return err
+	}
+	return u.addBlockItems(ctx, rv.items)
+}
+
+// scimUserAttrs SCIM用户中保存到账号和user_scim_user上的属性
+type scimUserAttrs struct {
+	userName   string
+	externalID string
+	name       string
+	alias      string
+	email      string
+	active     bool
+}
+
+// scimUserToAttrs 姓名优先使用name.formatted，其次是givenName和familyName，最后是displayName
+func scimUserToAttrs(in *scim.User) (*scimUserAttrs, error) {
+	attrs := &scimUserAttrs{
+		userName:   strings.TrimSpace(in.UserName),
+		externalID: in.ExternalID,
+		name:       in.DisplayName,
+		alias:      in.NickName,
+		email:      common.NormalizeEmail(in.PrimaryEmail()),
+		active:     in.Active == nil || *in.Active,
+	}
+	if attrs.userName == "" {
+		return nil, scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "userName is required")
+	}
+	if n := in.Name; n != nil {
+		if n.Formatted != "" {
+			attrs.name = n.Formatted
+		} else if given := strings.TrimSpace(n.GivenName + " " + n.FamilyName); given != "" {
+			attrs.name = given
+		}
+	}
+	if attrs.email != "" {
+		if addr, err := mail.ParseAddress(attrs.email); err != nil || addr.Address != attrs.email || common.IsPlaceholderEmail(attrs.email) {
+			return nil, scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "invalid email")
+		}
+	}
+	return attrs, nil
+}
+
+// saveScimUser 读取当前属性，通过build计算出新属性后保存
+func (u *UserService) saveScimUser(ctx context.Context, tenantID, id string, build func(current *scim.User) (*scimUserAttrs, error)) error {
+	rv := &revocation{}
+	err := u.db.Transaction(func(tx *query.Query) error {
+		link, userAccount, err := u.getScimUser(ctx, tx, tenantID, id)
+		if err != nil {
+			return err
+		}
+		attrs, err := build(scimPatchBase(link, userAccount))
+		if err != nil {
+			return err
+		}
+		if attrs.userName != link.UserName || attrs.externalID != link.ExternalID {
+			if err := u.checkScimUserName(ctx, tx, tenantID, attrs.userName, link.ID); err != nil {
+				return err
+			}
+			q := tx.UserScimUser
+			if _, err := q.WithContext(ctx).Where(q.ID.Eq(link.ID)).UpdateSimple(
+				q.UserName.Value(attrs.userName),
+				q.ExternalID.Value(attrs.externalID),
+			); err != nil {
+				if errors.Is(err, gorm.ErrDuplicatedKey) {
+					return ecode.ErrUserScimUserExists
+				}
+				return err
+			}
+		}
+		return u.updateScimAccount(ctx, tx, rv, userAccount, attrs)
+	})
+	if err != nil {
+		return err
+	}
+	return u.addBlockItems(ctx, rv.items)
+}
+
+func (u *UserService) checkScimUserName(ctx context.Context, tx *query.Query, tenantID, userName string, excludeID int64) error {
+	q := tx.UserScimUser
+	count, err := q.WithContext(ctx).Where(
+		q.TenantID.Eq(tenantID),
+		q.UserName.Eq(userName),
+		q.ID.Neq(excludeID),
+	).Count()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ecode.ErrUserScimUserExists
+	}
+	return nil
+}
+
+func (u *UserService) findOrCreateScimAccount(ctx context.Context, tx *query.Query, tenantID string, attrs *scimUserAttrs) (*model.UserAccount, bool, error) {
+	accountQ := tx.UserAccount
+	if attrs.email != "" {
+		existing, err := accountQ.WithContext(ctx).Where(
+			accountQ.TenantID.Eq(tenantID),
+			accountQ.Email.Eq(attrs.email),
+		).Take()
+		if err == nil {
+			// 未验证的邮箱不能证明是同一个人
+			if !existing.EmailVerified {
+				return nil, false, ecode.ErrUserEmailExists
+			}
+			return existing, false, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, err
+		}
+	}
+	number, err := u.ids.GetShortID(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	id, err := u.ids.GetGlobalID(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	userAccount := &model.UserAccount{
+		ID:         id,
+		TenantID:   tenantID,
+		Number:     number,
+		Phone:      common.PlaceholderPhone(id),
+		Email:      common.PlaceholderEmail(id),
+		Name:       optionalString(attrs.name),
+		Alias_:     optionalString(attrs.alias),
+		Status:     int16(enumsv1.UserStatus_USER_STATUS_OK),
+		Source:     int16(enumsv1.UserSource_USER_SOURCE_UNSPECIFIED),
+		SignupType: int16(enumsv1.SignUpType_SIGN_UP_TYPE_SCIM),
+	}
+	if attrs.email != "" {
+		userAccount.Email = attrs.email
+		userAccount.EmailVerified = true
+	}
+	if err := accountQ.WithContext(ctx).Create(userAccount); err != nil {
+		// 手机号和编号都是新生成的，冲突只可能是邮箱
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, false, ecode.ErrUserEmailExists
+		}
+		return nil, false, err
+	}
+	return userAccount, true, nil
+}
+
+// updateScimAccount 保存姓名、昵称、邮箱和启用状态，IdP是企业账号的权威来源，邮箱视为已验证
+func (u *UserService) updateScimAccount(ctx context.Context, tx *query.Query, rv *revocation, userAccount *model.UserAccount, attrs *scimUserAttrs) error {
+	accountQ := tx.UserAccount
+	updates := &model.UserAccount{}
+	var columns []field.Expr
+	var fields []string
+	if attrs.name != trans.Deref(userAccount.Name) {
+		updates.Name = optionalString(attrs.name)
+		columns = append(columns, accountQ.Name)
+		fields = append(fields, "name")
+	}
+	if attrs.alias != trans.Deref(userAccount.Alias_) {
+		updates.Alias_ = optionalString(attrs.alias)
+		columns = append(columns, accountQ.Alias_)
+		fields = append(fields, "alias")
+	}
+	if attrs.email != "" && (attrs.email != userAccount.Email || !userAccount.EmailVerified) {
+		count, err := accountQ.WithContext(ctx).Where(
+			accountQ.TenantID.Eq(userAccount.TenantID),
+			accountQ.Email.Eq(attrs.email),
+			accountQ.ID.Neq(userAccount.ID),
+		).Count()
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ecode.ErrUserEmailExists
+		}
+		updates.Email = attrs.email
+		updates.EmailVerified = true
+		columns = append(columns, accountQ.Email, accountQ.EmailVerified)
+		fields = append(fields, "email")
+	}
+	if len(columns) > 0 {
+		if _, err := accountQ.WithContext(ctx).Where(accountQ.ID.Eq(userAccount.ID)).Select(columns...).Updates(updates); err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ecode.ErrUserEmailExists
+			}
+			return err
+		}
+		updated, err := u.getUserAccount(ctx, tx, userAccount.ID)
+		if err != nil {
+			return err
+		}
+		if err := u.emitEvent(ctx, tx, enumsv1.UserEventType_USER_EVENT_TYPE_PROFILE_UPDATED, userAccount.TenantID, userAccount.ID, &userv1.UserProfileUpdatedEvent{
+			Fields: fields,
+			User:   common.UserModelToUser(updated),
+		}); err != nil {
+			return err
+		}
+	}
+	// 只在正常和禁用之间切换，注销中的账号不受影响
+	switch {
+	case !attrs.active && userAccount.Status == int16(enumsv1.UserStatus_USER_STATUS_OK):
+		return u.disableUser(ctx, tx, rv, userAccount)
+	case attrs.active && userAccount.Status == int16(enumsv1.UserStatus_USER_STATUS_DISABLED):
+		_, err := accountQ.WithContext(ctx).Where(accountQ.ID.Eq(userAccount.ID)).Update(accountQ.Status, int16(enumsv1.UserStatus_USER_STATUS_OK))
+		return err
+	}
+	return nil
+}
+
+func (u *UserService) getScimUser(ctx context.Context, tx *query.Query, tenantID, id string) (*model.UserScimUser, *model.UserAccount, error) {
+	uid, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, nil, ecode.ErrUserNotFound
+	}
+	q := tx.UserScimUser
+	link, err := q.WithContext(ctx).Where(q.TenantID.Eq(tenantID), q.UID.Eq(uid)).Take()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ecode.ErrUserNotFound
+		}
+		return nil, nil, err
+	}
+	userAccount, err := u.getUserAccount(ctx, tx, uid)
+	if err != nil {
+		return nil, nil, err
+	}
+	return link, userAccount, nil
+}
+
+// scimUsers 批量转换为SCIM用户，groups为用户在该租户下的角色
+func (u *UserService) scimUsers(ctx context.Context, tx *query.Query, tenantID string, links []*model.UserScimUser) ([]*scim.User, error) {
+	uids := make([]int64, 0, len(links))
+	for _, link := range links {
+		uids = append(uids, link.UID)
+	}
+	accountQ := tx.UserAccount
+	accounts, err := accountQ.WithContext(ctx).Where(accountQ.ID.In(uids...)).Find()
+	if err != nil {
+		return nil, err
+	}
+	accountMap := make(map[int64]*model.UserAccount, len(accounts))
+	for _, a := range accounts {
+		accountMap[a.ID] = a
+	}
+	bindingQ := tx.UserRoleBinding
+	bindings, err := bindingQ.WithContext(ctx).Where(bindingQ.UID.In(uids...)).Find()
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[int64][]scim.MultiValued, len(links))
+	if len(bindings) > 0 {
+		roleIDs := make([]int64, 0, len(bindings))
+		for _, b := range bindings {
+			roleIDs = append(roleIDs, b.RoleID)
+		}
+		roleQ := tx.UserRole
+		roles, err := roleQ.WithContext(ctx).Where(roleQ.ID.In(roleIDs...), roleQ.TenantID.Eq(tenantID)).Find()
+		if err != nil {
+			return nil, err
+		}
+		roleNames := make(map[int64]string, len(roles))
+		for _, r := range roles {
+			roleNames[r.ID] = r.Name
+		}
+		for _, b := range bindings {
+			if name, ok := roleNames[b.RoleID]; ok {
+				groups[b.UID] = append(groups[b.UID], scim.MultiValued{
+					Value:   strconv.FormatInt(b.RoleID, 10),
+					Display: name,
+				})
+			}
+		}
+	}
+	users := make([]*scim.User, 0, len(links))
+	for _, link := range links {
+		userAccount, ok := accountMap[link.UID]
+		if !ok {
+			continue
+		}
+		user := scimPatchBase(link, userAccount)
+		if user.DisplayName != "" {
+			user.Name = &scim.Name{Formatted: user.DisplayName}
+		}
+		user.Groups = groups[link.UID]
+		user.Meta = &scim.Meta{
+			ResourceType: scim.ResourceTypeUser,
+			Created:      userAccount.CreatedAt,
+			LastModified: userAccount.UpdatedAt,
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// scimPatchBase 账号当前的SCIM属性，name只通过displayName表示，PATCH修改name时才会覆盖displayName
+func scimPatchBase(link *model.UserScimUser, userAccount *model.UserAccount) *scim.User {
+	active := userAccount.Status != int16(enumsv1.UserStatus_USER_STATUS_DISABLED)
+	user := &scim.User{
+		Schemas:     []string{scim.SchemaUser},
+		ID:          strconv.FormatInt(userAccount.ID, 10),
+		ExternalID:  link.ExternalID,
+		UserName:    link.UserName,
+		DisplayName: trans.Deref(userAccount.Name),
+		NickName:    trans.Deref(userAccount.Alias_),
+		Active:      &active,
+	}
+	if !common.IsPlaceholderEmail(userAccount.Email) {
+		user.Emails = []scim.MultiValued{{Value: userAccount.Email, Type: "work", Primary: true}}
+	}
+	return user
+}
+
+// applyScimUserPatch 没有path时value是属性对象，不支持的属性直接忽略，IdP通常会同步很多这里不保存的属性
+func applyScimUserPatch(user *scim.User, ops []scim.PatchOperation) error {
+	for _, op := range ops {
+		if op.Path != "" {
+			path, err := scim.ParsePath(op.Path)
+			if err != nil {
+				return err
+			}
+			if err := setScimUserAttr(user, op.Op, path, op.Value); err != nil {
+				return err
+			}
+			continue
+		}
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(op.Value, &values); err != nil {
+			return scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "value must be an object")
+		}
+		for k, v := range values {
+			path, err := scim.ParsePath(k)
+			if err != nil {
+				return err
+			}
+			if err := setScimUserAttr(user, op.Op, path, v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func setScimUserAttr(user *scim.User, op string, path *scim.ValuePath, value json.RawMessage) error {
+	remove := op == scim.OpRemove
+	setString := func(dst *string) error {
+		if remove {
+			*dst = ""
+			return nil
+		}
+		s, err := scim.String(value)
+		if err != nil {
+			return err
+		}
+		*dst = s
+		return nil
+	}
+	switch path.Attr {
+	case "username":
+		if remove {
+			return scim.NewError(http.StatusBadRequest, scim.ErrTypeMutability, "userName is required")
+		}
+		return setString(&user.UserName)
+	case "externalid":
+		return setString(&user.ExternalID)
+	case "displayname":
+		return setString(&user.DisplayName)
+	case "nickname":
+		return setString(&user.NickName)
+	case "active":
+		if remove {
+			return scim.NewError(http.StatusBadRequest, scim.ErrTypeMutability, "active cannot be removed")
+		}
+		active, err := scim.Bool(value)
+		if err != nil {
+			return err
+		}
+		user.Active = &active
+	case "name":
+		if user.Name == nil {
+			user.Name = &scim.Name{}
+		}
+		switch path.SubAttr {
+		case "":
+			if remove {
+				user.Name = &scim.Name{}
+				return nil
+			}
+			var name scim.Name
+			if err := json.Unmarshal(value, &name); err != nil {
+				return scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "invalid name")
+			}
+			user.Name = &name
+		case "formatted":
+			return setString(&user.Name.Formatted)
+		case "givenname":
+			return setString(&user.Name.GivenName)
+		case "familyname":
+			return setString(&user.Name.FamilyName)
+		}
+	case "emails":
+		// 账号只保存一个邮箱，删除邮箱时保留原邮箱
+		if remove {
+			user.Emails = nil
+			return nil
+		}
+		if path.Filter != nil || path.SubAttr == "value" {
+			email, err := scim.String(value)
+			if err != nil {
+				return err
+			}
+			user.Emails = []scim.MultiValued{{Value: email, Primary: true}}
+			return nil
+		}
+		var emails []scim.MultiValued
+		if err := json.Unmarshal(value, &emails); err != nil {
+			return scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "invalid emails")
+		}
+		user.Emails = emails
+	}
+	return nil
+}
+
+// scimPage startIndex小于1时按1处理，count小于0表示没有传，使用默认值，超过上限时截断
+func scimPage(startIndex, count int) (int, int) {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count < 0 {
+		count = scimDefaultCount
+	}
+	if count > scimMaxCount {
+		count = scimMaxCount
+	}
+	return startIndex, count
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func scimTokenModelToScimToken(m *model.UserScimToken) *userv1.ScimToken {
+	token := &userv1.ScimToken{
+		Id:       m.ID,
+		TenantId: m.TenantID,
+		Name:     m.Name,
+		Prefix:   fmt.Sprintf("%s_%s", scimTokenScheme, m.Prefix),
+		Status:   enumsv1.ApiKeyStatus(m.Status),
+	}
+	if m.ExpiredAt != nil {
+		token.ExpiredAt = timestamppb.New(*m.ExpiredAt)
+	}
+	if m.LastUsedAt != nil {
+		token.LastUsedAt = timestamppb.New(*m.LastUsedAt)
+	}
+	if m.CreatedAt != nil {
+		token.CreatedAt = timestamppb.New(*m.CreatedAt)
+	}
+	return token
+}
@@ -0,0 +1,396 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"gorm.io/gen"
+	"gorm.io/gorm"
+
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/ecode"
+	"github.com/byteflowing/base/pkg/scim"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+// SCIM中的Group对应租户下的角色，displayName为角色名，members为绑定了该角色的用户
+// 成员变化和其他方式修改角色一样，在用户下次签发token时生效
+
+func (u *UserService) ScimGetGroup(ctx context.Context, tenantID, id string) (*scim.Group, error) {
+	role, err := u.getScimGroup(ctx, u.db, tenantID, id)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := u.scimGroups(ctx, u.db, []*model.UserRole{role})
+	if err != nil {
+		return nil, err
+	}
+	return groups[0], nil
+}
+
+// ScimListGroups 支持按displayName和id过滤
+func (u *UserService) ScimListGroups(ctx context.Context, tenantID, filter string, startIndex, count int) (*scim.ListResponse, error) {
+	conds, err := scim.ParseFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	startIndex, count = scimPage(startIndex, count)
+	q := u.db.UserRole
+	where := []gen.Condition{q.TenantID.Eq(tenantID)}
+	for _, c := range conds {
+		switch c.Attr {
+		case "displayname":
+			where = append(where, q.Name.Eq(c.Value))
+		case "id":
+			id, err := strconv.ParseInt(c.Value, 10, 64)
+			if err != nil {
+				return scim.NewListResponse(0, startIndex, nil), nil
+			}
+			where = append(where, q.ID.Eq(id))
+		default:
+			return nil, scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidFilter, "unsupported attribute "+c.Attr)
+		}
+	}
+	tx := q.WithContext(ctx).Where(where...)
+	total, err := tx.Count()
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 || total < int64(startIndex) {
+		return scim.NewListResponse(total, startIndex, nil), nil
+	}
+	roles, err := tx.Order(q.ID).Offset(startIndex - 1).Limit(count).Find()
+	if err != nil {
+		return nil, err
+	}
+	groups, err := u.scimGroups(ctx, u.db, roles)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]any, 0, len(groups))
+	for _, group := range groups {
+		resources = append(resources, group)
+	}
+	return scim.NewListResponse(total, startIndex, resources), nil
+}
+
+func (u *UserService) ScimCreateGroup(ctx context.Context, tenantID string, in *scim.Group) (*scim.Group, error) {
+	if in.DisplayName == "" {
+		return nil, scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "displayName is required")
+	}
+	members, err := parseScimMembers(in.Members)
+	if err != nil {
+		return nil, err
+	}
+	var roleID int64
+	err = u.db.Transaction(func(tx *query.Query) error {
+		if err := u.checkRoleName(ctx, tx, tenantID, in.DisplayName, 0); err != nil {
+			return err
+		}
+		role := &model.UserRole{TenantID: tenantID, Name: in.DisplayName}
+		if err := tx.UserRole.WithContext(ctx).Create(role); err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ecode.ErrUserRoleExists
+			}
+			return err
+		}
+		roleID = role.ID
+		return u.addRoleMembers(ctx, tx, role, members)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.ScimGetGroup(ctx, tenantID, strconv.FormatInt(roleID, 10))
+}
+
+// ScimReplaceGroup PUT整体替换角色名和成员
+func (u *UserService) ScimReplaceGroup(ctx context.Context, tenantID, id string, in *scim.Group) (*scim.Group, error) {
+	if in.DisplayName == "" {
+		return nil, scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "displayName is required")
+	}
+	members, err := parseScimMembers(in.Members)
+	if err != nil {
+		return nil, err
+	}
+	err = u.db.Transaction(func(tx *query.Query) error {
+		role, err := u.getScimGroup(ctx, tx, tenantID, id)
+		if err != nil {
+			return err
+		}
+		if err := u.renameRole(ctx, tx, role, in.DisplayName); err != nil {
+			return err
+		}
+		return u.setRoleMembers(ctx, tx, role, members)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.ScimGetGroup(ctx, tenantID, id)
+}
+
+// ScimPatchGroup 支持修改displayName，以及添加、替换和删除members
+func (u *UserService) ScimPatchGroup(ctx context.Context, tenantID, id string, patch *scim.PatchOp) (*scim.Group, error) {
+	if err := patch.Validate(); err != nil {
+		return nil, err
+	}
+	err := u.db.Transaction(func(tx *query.Query) error {
+		role, err := u.getScimGroup(ctx, tx, tenantID, id)
+		if err != nil {
+			return err
+		}
+		for _, op := range patch.Operations {
+			if op.Path != "" {
+				path, err := scim.ParsePath(op.Path)
+				if err != nil {
+					return err
+				}
+				if err := u.patchScimGroupAttr(ctx, tx, role, op.Op, path, op.Value); err != nil {
+					return err
+				}
+				continue
+			}
+			var values map[string]json.RawMessage
+			if err := json.Unmarshal(op.Value, &values); err != nil {
+				return scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "value must be an object")
+			}
+			for k, v := range values {
+				path, err := scim.ParsePath(k)
+				if err != nil {
+					return err
+				}
+				if err := u.patchScimGroupAttr(ctx, tx, role, op.Op, path, v); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u.ScimGetGroup(ctx, tenantID, id)
+}
+
+func (u *UserService) ScimDeleteGroup(ctx context.Context, tenantID, id string) error {
+	role, err := u.getScimGroup(ctx, u.db, tenantID, id)
+	if err != nil {
+		return err
+	}
+	_, err = u.DeleteRole(ctx, &userv1.DeleteRoleReq{RoleId: role.ID})
+	return err
+}
+
+// patchScimGroupAttr 不支持的属性直接忽略
+func (u *UserService) patchScimGroupAttr(
+	ctx context.Context,
+	tx *query.Query,
+	role *model.UserRole,
+	op string,
+	path *scim.ValuePath,
+	value json.RawMessage,
+) error {
+	switch path.Attr {
+	case "displayname":
+		if op == scim.OpRemove {
+			return scim.NewError(http.StatusBadRequest, scim.ErrTypeMutability, "displayName is required")
+		}
+		name, err := scim.String(value)
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			return scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "displayName is required")
+		}
+		return u.renameRole(ctx, tx, role, name)
+	case "members":
+		// members[value eq "uid"]只能用于删除指定成员
+		if path.Filter != nil {
+			if op != scim.OpRemove || path.Filter.Attr != "value" {
+				return scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidPath, "unsupported members filter")
+			}
+			uids, err := parseScimMembers([]scim.MultiValued{{Value: path.Filter.Value}})
+			if err != nil {
+				return err
+			}
+			return u.removeRoleMembers(ctx, tx, role, uids)
+		}
+		var members []scim.MultiValued
+		if len(value) > 0 {
+			if err := json.Unmarshal(value, &members); err != nil {
+				return scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "invalid members")
+			}
+		}
+		uids, err := parseScimMembers(members)
+		if err != nil {
+			return err
+		}
+		switch op {
+		case scim.OpAdd:
+			return u.addRoleMembers(ctx, tx, role, uids)
+		case scim.OpReplace:
+			return u.setRoleMembers(ctx, tx, role, uids)
+		default:
+			// 没有value时删除全部成员
+			if len(value) == 0 {
+				return u.setRoleMembers(ctx, tx, role, nil)
+			}
+			return u.removeRoleMembers(ctx, tx, role, uids)
+		}
+	}
+	return nil
+}
+
+// getScimGroup SCIM只能访问租户自己的角色，不能访问全局角色
+func (u *UserService) getScimGroup(ctx context.Context, tx *query.Query, tenantID, id string) (*model.UserRole, error) {
+	roleID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, ecode.ErrUserRoleNotFound
+	}
+	q := tx.UserRole
+	role, err := q.WithContext(ctx).Where(q.ID.Eq(roleID), q.TenantID.Eq(tenantID)).Take()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ecode.ErrUserRoleNotFound
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+func (u *UserService) scimGroups(ctx context.Context, tx *query.Query, roles []*model.UserRole) ([]*scim.Group, error) {
+	ids := make([]int64, 0, len(roles))
+	for _, r := range roles {
+		ids = append(ids, r.ID)
+	}
+	bindingQ := tx.UserRoleBinding
+	bindings, err := bindingQ.WithContext(ctx).Where(bindingQ.RoleID.In(ids...)).Order(bindingQ.ID).Find()
+	if err != nil {
+		return nil, err
+	}
+	members := make(map[int64][]scim.MultiValued, len(roles))
+	for _, b := range bindings {
+		members[b.RoleID] = append(members[b.RoleID], scim.MultiValued{Value: strconv.FormatInt(b.UID, 10)})
+	}
+	groups := make([]*scim.Group, 0, len(roles))
+	for _, r := range roles {
+		groups = append(groups, &scim.Group{
+			Schemas:     []string{scim.SchemaGroup},
+			ID:          strconv.FormatInt(r.ID, 10),
+			DisplayName: r.Name,
+			Members:     members[r.ID],
+			Meta: &scim.Meta{
+				ResourceType: scim.ResourceTypeGroup,
+				Created:      r.CreatedAt,
+				LastModified: r.UpdatedAt,
+			},
+		})
+	}
+	return groups, nil
+}
+
+func (u *UserService) checkRoleName(ctx context.Context, tx *query.Query, tenantID, name string, excludeID int64) error {
+	q := tx.UserRole
+	count, err := q.WithContext(ctx).Where(q.TenantID.Eq(tenantID), q.Name.Eq(name), q.ID.Neq(excludeID)).Count()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ecode.ErrUserRoleExists
+	}
+	return nil
+}
+
+func (u *UserService) renameRole(ctx context.Context, tx *query.Query, role *model.UserRole, name string) error {
+	if name == role.Name {
+		return nil
+	}
+	if err := u.checkRoleName(ctx, tx, role.TenantID, name, role.ID); err != nil {
+		return err
+	}
+	q := tx.UserRole
+	if _, err := q.WithContext(ctx).Where(q.ID.Eq(role.ID)).Update(q.Name, name); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ecode.ErrUserRoleExists
+		}
+		return err
+	}
+	role.Name = name
+	return nil
+}
+
+// addRoleMembers 成员必须是租户下的用户，已绑定的用户会被忽略
+func (u *UserService) addRoleMembers(ctx context.Context, tx *query.Query, role *model.UserRole, uids []int64) error {
+	if len(uids) == 0 {
+		return nil
+	}
+	accountQ := tx.UserAccount
+	count, err := accountQ.WithContext(ctx).Where(accountQ.ID.In(uids...), accountQ.TenantID.Eq(role.TenantID)).Count()
+	if err != nil {
+		return err
+	}
+	if count != int64(len(uids)) {
+		return scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "unknown member")
+	}
+	bindingQ := tx.UserRoleBinding
+	var bound []int64
+	if err := bindingQ.WithContext(ctx).Where(bindingQ.RoleID.Eq(role.ID), bindingQ.UID.In(uids...)).Pluck(bindingQ.UID, &bound); err != nil {
+		return err
+	}
+	var creates []*model.UserRoleBinding
+	for _, uid := range uids {
+		if slices.Contains(bound, uid) {
+			continue
+		}
+		creates = append(creates, &model.UserRoleBinding{
+			TenantID: role.TenantID,
+			UID:      uid,
+			RoleID:   role.ID,
+		})
+	}
+	if len(creates) == 0 {
+		return nil
+	}
+	return bindingQ.WithContext(ctx).Create(creates...)
+}
+
+func (u *UserService) removeRoleMembers(ctx context.Context, tx *query.Query, role *model.UserRole, uids []int64) error {
+	if len(uids) == 0 {
+		return nil
+	}
+	q := tx.UserRoleBinding
+	_, err := q.WithContext(ctx).Unscoped().Where(q.RoleID.Eq(role.ID), q.UID.In(uids...)).Delete()
+	return err
+}
+
+// setRoleMembers 使用uids覆盖角色原有的成员
+func (u *UserService) setRoleMembers(ctx context.Context, tx *query.Query, role *model.UserRole, uids []int64) error {
+	q := tx.UserRoleBinding
+	remove := q.WithContext(ctx).Unscoped().Where(q.RoleID.Eq(role.ID))
+	if len(uids) > 0 {
+		remove = remove.Where(q.UID.NotIn(uids...))
+	}
+	if _, err := remove.Delete(); err != nil {
+		return err
+	}
+	return u.addRoleMembers(ctx, tx, role, uids)
+}
+
+// parseScimMembers 成员的value为用户id，重复的成员只保留一个
+func parseScimMembers(members []scim.MultiValued) ([]int64, error) {
+	uids := make([]int64, 0, len(members))
+	for _, m := range members {
+		uid, err := strconv.ParseInt(m.Value, 10, 64)
+		if err != nil {
+			return nil, scim.NewError(http.StatusBadRequest, scim.ErrTypeInvalidValue, "invalid member "+m.Value)
+		}
+		if !slices.Contains(uids, uid) {
+			uids = append(uids, uid)
+		}
+	}
+	return uids, nil
+}
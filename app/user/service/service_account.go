@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+
+	"github.com/byteflowing/base/app/user/common"
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/ecode"
+	"github.com/byteflowing/base/pkg/utils/crypto"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+// API key格式为 bk_<prefix>_<secret>
+// prefix明文保存用于查找和展示，secret只保存sha256，创建时返回一次完整的key后无法再找回
+const (
+	apiKeyScheme      = "bk"
+	apiKeyPrefixBytes = 8
+	apiKeySecretBytes = 32
+
+	defaultServiceTokenTtl = 15 * time.Minute
+)
+
+func (u *UserService) CreateServiceAccount(ctx context.Context, req *userv1.CreateServiceAccountReq) (*userv1.CreateServiceAccountResp, error) {
+	if req.Name == "" {
+		return nil, ecode.ErrParams
+	}
+	if req.TenantId != "" {
+		if _, err := u.getTenantModel(ctx, req.TenantId); err != nil {
+			return nil, err
+		}
+	}
+	q := u.db.UserServiceAccount
+	_, err := q.WithContext(ctx).Where(q.TenantID.Eq(req.TenantId), q.Name.Eq(req.Name)).Take()
+	if err == nil {
+		return nil, ecode.ErrUserServiceAccountExists
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	m := &model.UserServiceAccount{
+		TenantID:    req.TenantId,
+		Name:        req.Name,
+		Description: req.Description,
+		Status:      int16(enumsv1.ServiceAccountStatus_SERVICE_ACCOUNT_STATUS_OK),
+	}
+	if err := q.WithContext(ctx).Create(m); err != nil {
+		return nil, err
+	}
+	return &userv1.CreateServiceAccountResp{Account: serviceAccountModelToServiceAccount(m)}, nil
+}
+
+// DisableServiceAccount 禁用后账号下的API key都无法再换取token，已签发的token在过期前仍然有效
+func (u *UserService) DisableServiceAccount(ctx context.Context, req *userv1.DisableServiceAccountReq) (*userv1.DisableServiceAccountResp, error) {
+	if err := u.setServiceAccountStatus(ctx, req.Id, enumsv1.ServiceAccountStatus_SERVICE_ACCOUNT_STATUS_DISABLED); err != nil {
+		return nil, err
+	}
+	return &userv1.DisableServiceAccountResp{}, nil
+}
+
+func (u *UserService) EnableServiceAccount(ctx context.Context, req *userv1.EnableServiceAccountReq) (*userv1.EnableServiceAccountResp, error) {
+	if err := u.setServiceAccountStatus(ctx, req.Id, enumsv1.ServiceAccountStatus_SERVICE_ACCOUNT_STATUS_OK); err != nil {
+		return nil, err
+	}
+	return &userv1.EnableServiceAccountResp{}, nil
+}
+
+func (u *UserService) ListServiceAccounts(ctx context.Context, req *userv1.ListServiceAccountsReq) (*userv1.ListServiceAccountsResp, error) {
+	q := u.db.UserServiceAccount
+	models, err := q.WithContext(ctx).Where(q.TenantID.Eq(req.TenantId)).Order(q.ID).Find()
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]*userv1.ServiceAccount, 0, len(models))
+	for _, m := range models {
+		accounts = append(accounts, serviceAccountModelToServiceAccount(m))
+	}
+	return &userv1.ListServiceAccountsResp{Accounts: accounts}, nil
+}
+
+// CreateApiKey 为服务账号创建API key，完整的key只在这里返回一次
+func (u *UserService) CreateApiKey(ctx context.Context, req *userv1.CreateApiKeyReq) (*userv1.CreateApiKeyResp, error) {
+	scopes := normalizeScopes(req.Scopes)
+	if len(scopes) == 0 {
+		return nil, ecode.ErrParams
+	}
+	if req.ExpiredAt != nil && !req.ExpiredAt.AsTime().After(time.Now()) {
+		return nil, ecode.ErrParams
+	}
+	account, err := u.getServiceAccount(ctx, u.db, req.AccountId)
+	if err != nil {
+		return nil, err
+	}
+	if account.Status != int16(enumsv1.ServiceAccountStatus_SERVICE_ACCOUNT_STATUS_OK) {
+		return nil, ecode.ErrUserServiceAccountDisabled
+	}
+	prefix, secret, err := newApiKey()
+	if err != nil {
+		return nil, err
+	}
+	m := &model.UserApiKey{
+		TenantID:   account.TenantID,
+		AccountID:  account.ID,
+		Prefix:     prefix,
+		SecretHash: crypto.Sha256Hex([]byte(secret)),
+		Scopes:     strings.Join(scopes, " "),
+		Status:     int16(enumsv1.ApiKeyStatus_API_KEY_STATUS_OK),
+	}
+	if req.ExpiredAt != nil {
+		expiredAt := req.ExpiredAt.AsTime()
+		m.ExpiredAt = &expiredAt
+	}
+	if err := u.db.UserApiKey.WithContext(ctx).Create(m); err != nil {
+		return nil, err
+	}
+	return &userv1.CreateApiKeyResp{
+		Key:    formatApiKey(apiKeyScheme, prefix, secret),
+		ApiKey: apiKeyModelToApiKey(m),
+	}, nil
+}
+
+// RevokeApiKey 吊销API key，吊销后不能恢复
+func (u *UserService) RevokeApiKey(ctx context.Context, req *userv1.RevokeApiKeyReq) (*userv1.RevokeApiKeyResp, error) {
+	q := u.db.UserApiKey
+	if _, err := q.WithContext(ctx).Where(q.ID.Eq(req.Id)).Take(); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ecode.ErrUserApiKeyNotFound
+		}
+		return nil, err
+	}
+	if _, err := q.WithContext(ctx).Where(q.ID.Eq(req.Id)).Update(q.Status, int16(enumsv1.ApiKeyStatus_API_KEY_STATUS_REVOKED)); err != nil {
+		return nil, err
+	}
+	return &userv1.RevokeApiKeyResp{}, nil
+}
+
+func (u *UserService) ListApiKeys(ctx context.Context, req *userv1.ListApiKeysReq) (*userv1.ListApiKeysResp, error) {
+	q := u.db.UserApiKey
+	models, err := q.WithContext(ctx).Where(q.AccountID.Eq(req.AccountId)).Order(q.ID).Find()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*userv1.ApiKey, 0, len(models))
+	for _, m := range models {
+		keys = append(keys, apiKeyModelToApiKey(m))
+	}
+	return &userv1.ListApiKeysResp{ApiKeys: keys}, nil
+}
+
+// ExchangeApiKey 使用API key换取短期的服务token
+// token的sub为服务账号id，scope为空时授予key的全部scope，否则必须是key的scope的子集
+// 调用方使用ValidateToken(type=TOKEN_TYPE_SERVICE)校验token并读取scopes
+func (u *UserService) ExchangeApiKey(ctx context.Context, req *userv1.ExchangeApiKeyReq) (*userv1.ExchangeApiKeyResp, error) {
+	prefix, secret, ok := parseApiKey(apiKeyScheme, req.ApiKey)
+	if !ok {
+		return nil, ecode.ErrUserApiKeyInvalid
+	}
+	q := u.db.UserApiKey
+	key, err := q.WithContext(ctx).Where(q.Prefix.Eq(prefix)).Take()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ecode.ErrUserApiKeyInvalid
+		}
+		return nil, err
+	}
+	hash := crypto.Sha256Hex([]byte(secret))
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(key.SecretHash)) != 1 {
+		return nil, ecode.ErrUserApiKeyInvalid
+	}
+	if key.Status != int16(enumsv1.ApiKeyStatus_API_KEY_STATUS_OK) {
+		return nil, ecode.ErrUserApiKeyInvalid
+	}
+	now := time.Now()
+	if key.ExpiredAt != nil && !key.ExpiredAt.After(now) {
+		return nil, ecode.ErrUserApiKeyExpired
+	}
+	account, err := u.getServiceAccount(ctx, u.db, key.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.Status != int16(enumsv1.ServiceAccountStatus_SERVICE_ACCOUNT_STATUS_OK) {
+		return nil, ecode.ErrUserServiceAccountDisabled
+	}
+	if account.TenantID != "" {
+		if _, err := u.getTenant(ctx, u.db, account.TenantID); err != nil {
+			return nil, err
+		}
+	}
+	granted := strings.Fields(key.Scopes)
+	scopes := normalizeScopes(req.Scopes)
+	if len(scopes) == 0 {
+		scopes = granted
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(granted, scope) {
+			return nil, ecode.ErrUserScopeNotAllowed
+		}
+	}
+	used := &model.UserApiKey{LastUsedAt: &now}
+	if req.Ip != "" {
+		used.LastUsedIP = &req.Ip
+	}
+	if _, err := q.WithContext(ctx).Where(q.ID.Eq(key.ID)).Updates(used); err != nil {
+		return nil, err
+	}
+	ttl := defaultServiceTokenTtl
+	if u.cfg.ServiceAccount != nil && u.cfg.ServiceAccount.TokenTtl != nil {
+		ttl = u.cfg.ServiceAccount.TokenTtl.AsDuration()
+	}
+	token, err := u.token.Generate(
+		strconv.FormatInt(account.ID, 10),
+		enumsv1.TokenType_TOKEN_TYPE_SERVICE.String(),
+		ttl,
+		map[string]any{
+			common.JwtTenantIDKey: account.TenantID,
+			common.JwtScopeKey:    strings.Join(scopes, " "),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.ExchangeApiKeyResp{
+		AccessToken: token.Token,
+		ExpiredAt:   timestamppb.New(token.Exp),
+		Scopes:      scopes,
+	}, nil
+}
+
+func (u *UserService) getServiceAccount(ctx context.Context, tx *query.Query, id int64) (*model.UserServiceAccount, error) {
+	q := tx.UserServiceAccount
+	account, err := q.WithContext(ctx).Where(q.ID.Eq(id)).Take()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ecode.ErrUserServiceAccountNotFound
+		}
+		return nil, err
+	}
+	return account, nil
+}
+
+func (u *UserService) setServiceAccountStatus(ctx context.Context, id int64, status enumsv1.ServiceAccountStatus) error {
+	if _, err := u.getServiceAccount(ctx, u.db, id); err != nil {
+		return err
+	}
+	q := u.db.UserServiceAccount
+	_, err := q.WithContext(ctx).Where(q.ID.Eq(id)).Update(q.Status, int16(status))
+	return err
+}
+
+func newApiKey() (prefix, secret string, err error) {
+	b := make([]byte, apiKeyPrefixBytes+apiKeySecretBytes)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", err
+	}
+	prefix = hex.EncodeToString(b[:apiKeyPrefixBytes])
+	secret = base64.RawURLEncoding.EncodeToString(b[apiKeyPrefixBytes:])
+	return prefix, secret, nil
+}
+
+func formatApiKey(scheme, prefix, secret string) string {
+	return fmt.Sprintf("%s_%s_%s", scheme, prefix, secret)
+}
+
+// parseApiKey secret是base64url编码，可能包含下划线，所以只切分前两段
+func parseApiKey(scheme, key string) (prefix, secret string, ok bool) {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != scheme || parts[1] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[2], true
+}
+
+// normalizeScopes 去掉空白和重复的scope，scope中不能包含空格
+func normalizeScopes(scopes []string) []string {
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if scope == "" || strings.ContainsAny(scope, " \t") || slices.Contains(result, scope) {
+			continue
+		}
+		result = append(result, scope)
+	}
+	return result
+}
+
+func serviceAccountModelToServiceAccount(m *model.UserServiceAccount) *userv1.ServiceAccount {
+	account := &userv1.ServiceAccount{
+		Id:          m.ID,
+		TenantId:    m.TenantID,
+		Name:        m.Name,
+		Description: m.Description,
+		Status:      enumsv1.ServiceAccountStatus(m.Status),
+	}
+	if m.CreatedAt != nil {
+		account.CreatedAt = timestamppb.New(*m.CreatedAt)
+	}
+	return account
+}
+
+func apiKeyModelToApiKey(m *model.UserApiKey) *userv1.ApiKey {
+	key := &userv1.ApiKey{
+		Id:         m.ID,
+		AccountId:  m.AccountID,
+		Prefix:     fmt.Sprintf("%s_%s", apiKeyScheme, m.Prefix),
+		Scopes:     strings.Fields(m.Scopes),
+		Status:     enumsv1.ApiKeyStatus(m.Status),
+		LastUsedIp: m.LastUsedIP,
+	}
+	if m.ExpiredAt != nil {
+		key.ExpiredAt = timestamppb.New(*m.ExpiredAt)
+	}
+	if m.LastUsedAt != nil {
+		key.LastUsedAt = timestamppb.New(*m.LastUsedAt)
+	}
+	if m.CreatedAt != nil {
+		key.CreatedAt = timestamppb.New(*m.CreatedAt)
+	}
+	return key
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gen/field"
+
+	"github.com/byteflowing/base/app/user/common"
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/pkg/db"
+	"github.com/byteflowing/base/pkg/logx"
+	"github.com/byteflowing/base/pkg/redis"
+	"github.com/byteflowing/base/singleton"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+const (
+	defaultRecentSignIns = 10
+	maxRecentSignIns     = 50
+
+	defaultRetentionBatchSize = 1000
+	signLogRetentionLockKey   = "sign_log_retention"
+	signLogRetentionLockTTL   = time.Hour
+)
+
+func (u *UserService) PagingGetSignLogs(ctx context.Context, req *userv1.PagingGetSignLogsReq) (*userv1.PagingGetSignLogsResp, error) {
+	q := u.db.UserSignLog
+	tx := q.WithContext(ctx)
+	if req.Asc {
+		tx = tx.Order(q.ID.Asc())
+	} else {
+		tx = tx.Order(q.ID.Desc())
+	}
+	if req.TenantId != nil {
+		tx = tx.Where(q.TenantID.Eq(req.GetTenantId()))
+	}
+	if req.Uid != nil {
+		tx = tx.Where(q.UID.Eq(req.GetUid()))
+	}
+	if req.Type != nil {
+		tx = tx.Where(q.Type.Eq(int16(req.GetType())))
+	}
+	if req.Status != nil {
+		tx = tx.Where(q.Status.Eq(int16(req.GetStatus())))
+	}
+	if req.Ip != nil {
+		tx = tx.Where(q.IP.Eq(req.GetIp()))
+	}
+	if req.Start != nil {
+		tx = tx.Where(q.CreatedAt.Gte(req.Start.AsTime()))
+	}
+	if req.End != nil {
+		tx = tx.Where(q.CreatedAt.Lt(req.End.AsTime()))
+	}
+	result, err := db.Paginate[model.UserSignLog](tx.UnderlyingDB(), uint32(req.Page), uint32(req.Size))
+	if err != nil {
+		return nil, err
+	}
+	logs := make([]*userv1.SignLog, 0, len(result.List))
+	for _, item := range result.List {
+		logs = append(logs, u.signLogModelToSignLog(item, ""))
+	}
+	return &userv1.PagingGetSignLogsResp{
+		Page:       int32(result.Page),
+		Size:       int32(result.PageSize),
+		Total:      int64(result.Total),
+		TotalPages: int32(result.TotalPages),
+		Logs:       logs,
+	}, nil
+}
+
+// GetMyRecentSignIns 用户查看自己最近的登录记录，Current标记当前token对应的登录
+func (u *UserService) GetMyRecentSignIns(ctx context.Context, req *userv1.GetMyRecentSignInsReq) (*userv1.GetMyRecentSignInsResp, error) {
+	uid, claims, err := u.parseAccessToken(ctx, req.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultRecentSignIns
+	}
+	if limit > maxRecentSignIns {
+		limit = maxRecentSignIns
+	}
+	q := u.db.UserSignLog
+	items, err := q.WithContext(ctx).Where(q.UID.Eq(uid)).Order(q.ID.Desc()).Limit(limit).Find()
+	if err != nil {
+		return nil, err
+	}
+	jti := common.GetJwtJti(claims)
+	logs := make([]*userv1.SignLog, 0, len(items))
+	for _, item := range items {
+		logs = append(logs, u.signLogModelToSignLog(item, jti))
+	}
+	return &userv1.GetMyRecentSignInsResp{Logs: logs}, nil
+}
+
+func (u *UserService) signLogModelToSignLog(m *model.UserSignLog, currentJti string) *userv1.SignLog {
+	l := &userv1.SignLog{
+		Id:         m.ID,
+		TenantId:   m.TenantID,
+		Uid:        m.UID,
+		Type:       enumsv1.SignInType(m.Type),
+		Status:     enumsv1.SignInStatus(m.Status),
+		Identifier: m.Identifier,
+		Agent: &userv1.Agent{
+			Ip:       m.IP,
+			Agent:    m.Agent,
+			Device:   m.Device,
+			Location: common.ParseLocationFromString(m.Location),
+		},
+		Current: currentJti != "" && m.AccessJti == currentJti,
+	}
+	if m.CreatedAt != nil {
+		l.CreatedAt = timestamppb.New(*m.CreatedAt)
+	}
+	return l
+}
+
+// startSignLogRetention 注册登录日志清理的定时任务
+func (u *UserService) startSignLogRetention() {
+	lock := redis.NewLock(u.rdb, &redis.LockOption{
+		Prefix: u.cfg.KeyPrefix + ":lock",
+		Tries:  1,
+		TTL:    signLogRetentionLockTTL,
+	})
+	job := func() {
+		ctx := context.Background()
+		// 多实例部署时只有拿到锁的实例执行清理
+		identifier, err := lock.Acquire(ctx, signLogRetentionLockKey)
+		if err != nil {
+			return
+		}
+		defer func() {
+			_ = lock.Release(ctx, signLogRetentionLockKey, identifier)
+		}()
+		count, err := u.cleanSignLogs(ctx)
+		if err != nil {
+			logx.CtxError(ctx, "clean sign logs failed", zap.Int("count", count), zap.Error(err))
+			return
+		}
+		logx.CtxInfo(ctx, "clean sign logs done", zap.Int("count", count))
+	}
+	if _, err := singleton.NewCron().AddFunc(u.cfg.SignLogRetention.Spec, job); err != nil {
+		panic(err)
+	}
+}
+
+// cleanSignLogs 分批清理超过保留时长的登录日志，开启归档时先写入user_sign_log_archive再删除
+// 每批在独立的事务中按主键删除，避免长时间锁表，refresh token未过期的登录不会被清理
+func (u *UserService) cleanSignLogs(ctx context.Context) (count int, err error) {
+	cfg := u.cfg.SignLogRetention
+	batchSize := int(cfg.BatchSize)
+	if batchSize <= 0 {
+		batchSize = defaultRetentionBatchSize
+	}
+	now := time.Now()
+	cutoff := now.Add(-cfg.MaxAge.AsDuration())
+	for {
+		var ids []int64
+		q := u.db.UserSignLog
+		if err = q.WithContext(ctx).Unscoped().Where(
+			q.CreatedAt.Lt(cutoff),
+			field.Or(q.RefreshExpiredAt.IsNull(), q.RefreshExpiredAt.Lt(now)),
+		).Order(q.ID).Limit(batchSize).Pluck(q.ID, &ids); err != nil {
+			return
+		}
+		if len(ids) == 0 {
+			return
+		}
+		if err = u.db.Transaction(func(tx *query.Query) error {
+			return u.removeSignLogs(ctx, tx, ids, cfg.Archive)
+		}); err != nil {
+			return
+		}
+		count += len(ids)
+		if len(ids) < batchSize {
+			return
+		}
+		if interval := cfg.BatchInterval.AsDuration(); interval > 0 {
+			time.Sleep(interval)
+		}
+	}
+}
+
+func (u *UserService) removeSignLogs(ctx context.Context, tx *query.Query, ids []int64, archive bool) error {
+	q := tx.UserSignLog
+	if archive {
+		logs, err := q.WithContext(ctx).Unscoped().Where(q.ID.In(ids...)).Find()
+		if err != nil {
+			return err
+		}
+		archives := make([]*model.UserSignLogArchive, 0, len(logs))
+		for _, l := range logs {
+			archives = append(archives, &model.UserSignLogArchive{
+				ID:               l.ID,
+				TenantID:         l.TenantID,
+				UID:              l.UID,
+				Type:             l.Type,
+				Status:           l.Status,
+				Identifier:       l.Identifier,
+				IP:               l.IP,
+				Location:         l.Location,
+				Agent:            l.Agent,
+				Device:           l.Device,
+				AccessJti:        l.AccessJti,
+				RefreshJti:       l.RefreshJti,
+				AccessExpiredAt:  l.AccessExpiredAt,
+				RefreshExpiredAt: l.RefreshExpiredAt,
+				UpdatedAt:        l.UpdatedAt,
+				CreatedAt:        l.CreatedAt,
+				DeletedAt:        l.DeletedAt,
+			})
+		}
+		if err := tx.UserSignLogArchive.WithContext(ctx).CreateInBatches(archives, len(archives)); err != nil {
+			return err
+		}
+	}
+	_, err := q.WithContext(ctx).Unscoped().Where(q.ID.In(ids...)).Delete()
+	return err
+}
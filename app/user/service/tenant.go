@@ -0,0 +1,487 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+
+	"github.com/byteflowing/base/app/user/auth"
+	"github.com/byteflowing/base/app/user/auth/ldap"
+	"github.com/byteflowing/base/app/user/common"
+	"github.com/byteflowing/base/app/user/dal/model"
+	"github.com/byteflowing/base/app/user/dal/query"
+	"github.com/byteflowing/base/ecode"
+	"github.com/byteflowing/base/pkg/logx"
+	enumsv1 "github.com/byteflowing/proto/gen/go/enums/v1"
+	userv1 "github.com/byteflowing/proto/gen/go/user/v1"
+)
+
+const (
+	tenantSecretMask   = "******"
+	tenantSecretPrefix = "enc:" // 加密后的密钥，格式为enc:base64(nonce+ciphertext)
+	// 被替换的租户配置延迟关闭，正在处理的请求可能还在使用其中的登录方式
+	tenantEntryCloseDelay = time.Minute
+)
+
+// tenantRegistry 缓存租户配置以及按租户凭证创建的认证方式
+// 每次使用时都会查询租户记录，updated_at没有变化时复用缓存，多实例部署时配置修改也能及时生效
+type tenantRegistry struct {
+	mu      sync.Mutex
+	entries map[string]*tenantEntry
+	newAuth func(cfg *userv1.AuthConfig) (auth.Auth, error)
+}
+
+type tenantEntry struct {
+	updatedAt      time.Time
+	accessTtl      time.Duration
+	refreshTtl     time.Duration
+	providers      map[enumsv1.SignInType]auth.Auth
+	owned          []auth.Auth // 按租户凭证创建的登录方式，缓存失效后需要关闭
+	passwordPolicy *userv1.PasswordPolicy
+	claimMappings  []*userv1.ClaimMapping
+}
+
+func newTenantRegistry(newAuth func(cfg *userv1.AuthConfig) (auth.Auth, error)) *tenantRegistry {
+	return &tenantRegistry{
+		entries: make(map[string]*tenantEntry),
+		newAuth: newAuth,
+	}
+}
+
+func (u *UserService) CreateTenant(ctx context.Context, req *userv1.CreateTenantReq) (*userv1.CreateTenantResp, error) {
+	if req.TenantId == "" || req.Name == "" {
+		return nil, ecode.ErrParams
+	}
+	settings, err := u.marshalTenantSettings(req.Settings, nil)
+	if err != nil {
+		return nil, err
+	}
+	q := u.db.UserTenant
+	_, err = q.WithContext(ctx).Where(q.TenantID.Eq(req.TenantId)).Take()
+	if err == nil {
+		return nil, ecode.ErrUserTenantExists
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	tenant := &model.UserTenant{
+		TenantID: req.TenantId,
+		Name:     req.Name,
+		Status:   int16(enumsv1.TenantStatus_TENANT_STATUS_OK),
+		Settings: settings,
+	}
+	if err := q.WithContext(ctx).Create(tenant); err != nil {
+		return nil, err
+	}
+	t, err := u.tenantModelToTenant(tenant)
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.CreateTenantResp{Tenant: t}, nil
+}
+
+func (u *UserService) GetTenant(ctx context.Context, req *userv1.GetTenantReq) (*userv1.GetTenantResp, error) {
+	tenant, err := u.getTenantModel(ctx, req.TenantId)
+	if err != nil {
+		return nil, err
+	}
+	t, err := u.tenantModelToTenant(tenant)
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.GetTenantResp{Tenant: t}, nil
+}
+
+// UpdateTenantSettings 修改租户名称和配置，settings会整体覆盖
+// 密钥字段为查询时返回的掩码时保留原来的密钥
+func (u *UserService) UpdateTenantSettings(ctx context.Context, req *userv1.UpdateTenantSettingsReq) (*userv1.UpdateTenantSettingsResp, error) {
+	old, err := u.getTenantModel(ctx, req.TenantId)
+	if err != nil {
+		return nil, err
+	}
+	oldSettings, err := u.unmarshalTenantSettings(old.Settings)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := u.marshalTenantSettings(req.Settings, oldSettings)
+	if err != nil {
+		return nil, err
+	}
+	q := u.db.UserTenant
+	tx := q.WithContext(ctx).Where(q.TenantID.Eq(req.TenantId))
+	updates := &model.UserTenant{Settings: settings}
+	if req.Name != nil {
+		if req.GetName() == "" {
+			return nil, ecode.ErrParams
+		}
+		updates.Name = req.GetName()
+		tx = tx.Select(q.Name, q.Settings)
+	} else {
+		tx = tx.Select(q.Settings)
+	}
+	if _, err := tx.Updates(updates); err != nil {
+		return nil, err
+	}
+	tenant, err := u.getTenantModel(ctx, req.TenantId)
+	if err != nil {
+		return nil, err
+	}
+	t, err := u.tenantModelToTenant(tenant)
+	if err != nil {
+		return nil, err
+	}
+	return &userv1.UpdateTenantSettingsResp{Tenant: t}, nil
+}
+
+// DisableTenant 禁用租户，禁用后该租户下的用户不能登录和刷新token
+func (u *UserService) DisableTenant(ctx context.Context, req *userv1.DisableTenantReq) (*userv1.DisableTenantResp, error) {
+	if err := u.setTenantStatus(ctx, req.TenantId, enumsv1.TenantStatus_TENANT_STATUS_DISABLED); err != nil {
+		return nil, err
+	}
+	return &userv1.DisableTenantResp{}, nil
+}
+
+func (u *UserService) EnableTenant(ctx context.Context, req *userv1.EnableTenantReq) (*userv1.EnableTenantResp, error) {
+	if err := u.setTenantStatus(ctx, req.TenantId, enumsv1.TenantStatus_TENANT_STATUS_OK); err != nil {
+		return nil, err
+	}
+	return &userv1.EnableTenantResp{}, nil
+}
+
+func (u *UserService) setTenantStatus(ctx context.Context, tenantID string, status enumsv1.TenantStatus) error {
+	if _, err := u.getTenantModel(ctx, tenantID); err != nil {
+		return err
+	}
+	q := u.db.UserTenant
+	_, err := q.WithContext(ctx).Where(q.TenantID.Eq(tenantID)).Update(q.Status, int16(status))
+	return err
+}
+
+func (u *UserService) getTenantModel(ctx context.Context, tenantID string) (*model.UserTenant, error) {
+	q := u.db.UserTenant
+	tenant, err := q.WithContext(ctx).Where(q.TenantID.Eq(tenantID)).Take()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ecode.ErrUserTenantNotFound
+		}
+		return nil, err
+	}
+	return tenant, nil
+}
+
+// getTenant 获取租户生效的配置
+// tenantID为空时使用全局配置，否则租户必须存在且处于启用状态
+func (u *UserService) getTenant(ctx context.Context, tx *query.Query, tenantID string) (*tenantEntry, error) {
+	if tenantID == "" {
+		return u.defaultTenant, nil
+	}
+	q := tx.UserTenant
+	tenant, err := q.WithContext(ctx).Where(q.TenantID.Eq(tenantID)).Take()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ecode.ErrUserTenantNotFound
+		}
+		return nil, err
+	}
+	if tenant.Status != int16(enumsv1.TenantStatus_TENANT_STATUS_OK) {
+		return nil, ecode.ErrUserTenantDisabled
+	}
+	var updatedAt time.Time
+	if tenant.UpdatedAt != nil {
+		updatedAt = *tenant.UpdatedAt
+	}
+	u.tenants.mu.Lock()
+	entry, ok := u.tenants.entries[tenantID]
+	u.tenants.mu.Unlock()
+	if ok && entry.updatedAt.Equal(updatedAt) {
+		return entry, nil
+	}
+	// 创建登录方式时不持有锁，避免阻塞其他租户
+	settings, err := u.unmarshalTenantSettings(tenant.Settings)
+	if err != nil {
+		return nil, err
+	}
+	entry, err = u.newTenantEntry(ctx, tenantID, settings)
+	if err != nil {
+		return nil, err
+	}
+	entry.updatedAt = updatedAt
+	u.tenants.mu.Lock()
+	old, ok := u.tenants.entries[tenantID]
+	if ok && !old.updatedAt.Before(updatedAt) {
+		// 其他请求已经缓存了相同或者更新的配置
+		u.tenants.mu.Unlock()
+		closeTenantEntry(ctx, tenantID, entry)
+		return old, nil
+	}
+	u.tenants.entries[tenantID] = entry
+	u.tenants.mu.Unlock()
+	if ok && len(old.owned) > 0 {
+		closeCtx := context.WithoutCancel(ctx)
+		time.AfterFunc(tenantEntryCloseDelay, func() {
+			closeTenantEntry(closeCtx, tenantID, old)
+		})
+	}
+	return entry, nil
+}
+
+// closeTenantEntry 关闭按租户凭证创建的登录方式，全局的登录方式不会被关闭
+func closeTenantEntry(ctx context.Context, tenantID string, entry *tenantEntry) {
+	for _, provider := range entry.owned {
+		closer, ok := provider.(auth.Closer)
+		if !ok {
+			continue
+		}
+		if err := closer.Close(); err != nil {
+			logx.CtxWarn(ctx, "close tenant auth provider failed", zap.String("tenantID", tenantID), zap.Error(err))
+		}
+	}
+}
+
+// newTenantEntry 在全局配置的基础上叠加租户配置
+// sign_in_types不为空时只开放列出的登录方式，auth中的凭证覆盖同类型的全局凭证
+// 创建登录方式失败时关闭已经创建的登录方式
+func (u *UserService) newTenantEntry(ctx context.Context, tenantID string, settings *userv1.TenantSettings) (*tenantEntry, error) {
+	entry := &tenantEntry{
+		accessTtl:      u.defaultTenant.accessTtl,
+		refreshTtl:     u.defaultTenant.refreshTtl,
+		providers:      make(map[enumsv1.SignInType]auth.Auth, len(u.defaultTenant.providers)),
+		passwordPolicy: u.defaultTenant.passwordPolicy,
+		claimMappings:  u.defaultTenant.claimMappings,
+	}
+	for k, v := range u.defaultTenant.providers {
+		entry.providers[k] = v
+	}
+	if settings == nil {
+		return entry, nil
+	}
+	if settings.AccessTtl != nil {
+		entry.accessTtl = settings.AccessTtl.AsDuration()
+	}
+	if settings.RefreshTtl != nil {
+		entry.refreshTtl = settings.RefreshTtl.AsDuration()
+	}
+	// 租户的密码策略整体覆盖全局策略
+	if settings.PasswordPolicy != nil {
+		entry.passwordPolicy = settings.PasswordPolicy
+	}
+	// 租户配置了claim映射时替换全局映射
+	if len(settings.ClaimMappings) > 0 {
+		entry.claimMappings = settings.ClaimMappings
+	}
+	var enabled map[enumsv1.SignInType]bool
+	if len(settings.SignInTypes) > 0 {
+		enabled = make(map[enumsv1.SignInType]bool, len(settings.SignInTypes))
+		for _, typ := range settings.SignInTypes {
+			enabled[typ] = true
+		}
+		for typ := range entry.providers {
+			if !enabled[typ] {
+				delete(entry.providers, typ)
+			}
+		}
+	}
+	for _, v := range settings.Auth {
+		// 未开放的登录方式不需要创建
+		if enabled != nil && !enabled[v.Type] {
+			continue
+		}
+		provider, err := u.tenants.newAuth(v)
+		if err != nil {
+			closeTenantEntry(ctx, tenantID, entry)
+			return nil, err
+		}
+		if provider != nil {
+			entry.providers[v.Type] = provider
+			entry.owned = append(entry.owned, provider)
+		}
+	}
+	return entry, nil
+}
+
+// marshalTenantSettings 校验租户配置并加密其中的密钥
+// old为修改前的配置，用于还原掩码对应的密钥
+func (u *UserService) marshalTenantSettings(settings, old *userv1.TenantSettings) (*string, error) {
+	if settings == nil {
+		return nil, nil
+	}
+	if settings.AccessTtl != nil && settings.AccessTtl.AsDuration() <= 0 {
+		return nil, ecode.ErrParams
+	}
+	if settings.RefreshTtl != nil && settings.RefreshTtl.AsDuration() <= 0 {
+		return nil, ecode.ErrParams
+	}
+	if policy := settings.PasswordPolicy; policy != nil && (policy.MinLength < 0 || policy.HistorySize < 0) {
+		return nil, ecode.ErrParams
+	}
+	if !common.ValidateClaimMappings(settings.ClaimMappings) {
+		return nil, ecode.ErrParams
+	}
+	settings = proto.Clone(settings).(*userv1.TenantSettings)
+	oldSecrets := tenantSecrets(old)
+	for key, secret := range tenantSecrets(settings) {
+		if *secret != tenantSecretMask {
+			continue
+		}
+		oldSecret, ok := oldSecrets[key]
+		if !ok {
+			return nil, ecode.ErrParams
+		}
+		*secret = *oldSecret
+	}
+	for _, v := range settings.Auth {
+		if !validateAuthConfig(v) {
+			return nil, ecode.ErrParams
+		}
+	}
+	for _, secret := range tenantSecrets(settings) {
+		if *secret == "" {
+			continue
+		}
+		encrypted, err := u.encryptSecret(*secret)
+		if err != nil {
+			return nil, err
+		}
+		*secret = encrypted
+	}
+	data, err := protojson.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+	s := string(data)
+	return &s, nil
+}
+
+// unmarshalTenantSettings 解析租户配置并解密其中的密钥
+func (u *UserService) unmarshalTenantSettings(settings *string) (*userv1.TenantSettings, error) {
+	if settings == nil || *settings == "" {
+		return nil, nil
+	}
+	s := &userv1.TenantSettings{}
+	if err := protojson.Unmarshal([]byte(*settings), s); err != nil {
+		return nil, err
+	}
+	for _, secret := range tenantSecrets(s) {
+		plaintext, err := u.decryptSecret(*secret)
+		if err != nil {
+			return nil, err
+		}
+		*secret = plaintext
+	}
+	return s, nil
+}
+
+// encryptSecret 使用tenant_secret_key加密需要落库的密钥
+func (u *UserService) encryptSecret(plaintext string) (string, error) {
+	if u.secrets == nil {
+		return "", ecode.ErrUserTenantSecretKeyMissing
+	}
+	encrypted, err := u.secrets.Encrypt([]byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return tenantSecretPrefix + base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+// decryptSecret 解密encryptSecret加密的密钥，没有前缀的是加密之前保存的明文
+func (u *UserService) decryptSecret(stored string) (string, error) {
+	if !strings.HasPrefix(stored, tenantSecretPrefix) {
+		return stored, nil
+	}
+	if u.secrets == nil {
+		return "", ecode.ErrUserTenantSecretKeyMissing
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(stored, tenantSecretPrefix))
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := u.secrets.Decrypt(data)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// tenantSecrets 返回租户配置中的密钥字段，key用于修改配置时匹配原来的密钥
+func tenantSecrets(settings *userv1.TenantSettings) map[string]*string {
+	secrets := make(map[string]*string)
+	if settings == nil {
+		return secrets
+	}
+	for _, v := range settings.Auth {
+		typ := v.Type.String()
+		switch v.Type {
+		case enumsv1.SignInType_SIGN_IN_TYPE_WECHAT_MINI:
+			for _, c := range v.GetWechat().GetCredentials() {
+				secrets[typ+"/"+c.Appid] = &c.Secret
+			}
+		case enumsv1.SignInType_SIGN_IN_TYPE_HUAWEI:
+			if v.Huawei != nil {
+				secrets[typ] = &v.Huawei.ClientSecret
+			}
+		case enumsv1.SignInType_SIGN_IN_TYPE_OIDC:
+			if v.Oidc != nil {
+				secrets[typ] = &v.Oidc.ClientSecret
+			}
+		case enumsv1.SignInType_SIGN_IN_TYPE_LDAP:
+			if v.Ldap != nil {
+				secrets[typ] = &v.Ldap.BindPassword
+			}
+		}
+	}
+	return secrets
+}
+
+// validateAuthConfig 校验认证配置是否完整，不创建认证方式
+func validateAuthConfig(v *userv1.AuthConfig) bool {
+	switch v.Type {
+	case enumsv1.SignInType_SIGN_IN_TYPE_WECHAT_MINI:
+		return v.Wechat != nil
+	case enumsv1.SignInType_SIGN_IN_TYPE_HUAWEI:
+		return v.Huawei != nil && v.Huawei.ClientId != "" && v.Huawei.ClientSecret != ""
+	case enumsv1.SignInType_SIGN_IN_TYPE_APPLE:
+		return v.Apple != nil && len(v.Apple.ClientIds) > 0
+	case enumsv1.SignInType_SIGN_IN_TYPE_GUEST:
+		return true
+	case enumsv1.SignInType_SIGN_IN_TYPE_OIDC:
+		return v.Oidc != nil && v.Oidc.Issuer != "" && v.Oidc.ClientId != ""
+	case enumsv1.SignInType_SIGN_IN_TYPE_LDAP:
+		return v.Ldap != nil && ldap.ValidateConfig(v.Ldap) == nil
+	}
+	return false
+}
+
+// tenantModelToTenant 返回的配置中密钥替换为掩码
+func (u *UserService) tenantModelToTenant(m *model.UserTenant) (*userv1.Tenant, error) {
+	settings, err := u.unmarshalTenantSettings(m.Settings)
+	if err != nil {
+		return nil, err
+	}
+	for _, secret := range tenantSecrets(settings) {
+		if *secret != "" {
+			*secret = tenantSecretMask
+		}
+	}
+	t := &userv1.Tenant{
+		TenantId: m.TenantID,
+		Name:     m.Name,
+		Status:   enumsv1.TenantStatus(m.Status),
+		Settings: settings,
+	}
+	if m.UpdatedAt != nil {
+		t.UpdatedAt = timestamppb.New(*m.UpdatedAt)
+	}
+	if m.CreatedAt != nil {
+		t.CreatedAt = timestamppb.New(*m.CreatedAt)
+	}
+	return t, nil
+}
@@ -4,14 +4,27 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/mail"
 	"strconv"
+	"strings"
 	"time"
 
+	"go.uber.org/zap"
+	"gorm.io/gen"
 	"gorm.io/gorm"
 
+	"github.com/byteflowing/base/app/geo"
+	geoService "github.com/byteflowing/base/app/geo/service"
 	"github.com/byteflowing/base/app/global_id"
+	"github.com/byteflowing/base/app/message"
+	"github.com/byteflowing/base/app/message/queue"
+	messageService "github.com/byteflowing/base/app/message/service"
 	"github.com/byteflowing/base/app/user/auth"
+	"github.com/byteflowing/base/app/user/auth/apple"
+	"github.com/byteflowing/base/app/user/auth/guest"
 	"github.com/byteflowing/base/app/user/auth/huawei"
+	"github.com/byteflowing/base/app/user/auth/ldap"
+	"github.com/byteflowing/base/app/user/auth/oidc"
 	"github.com/byteflowing/base/app/user/auth/tencent"
 	"github.com/byteflowing/base/app/user/common"
 	"github.com/byteflowing/base/app/user/dal/model"
@@ -20,6 +33,10 @@ import (
 	"github.com/byteflowing/base/ecode"
 	"github.com/byteflowing/base/pkg/blocklist"
 	"github.com/byteflowing/base/pkg/jwt"
+	"github.com/byteflowing/base/pkg/logx"
+	"github.com/byteflowing/base/pkg/redis"
+	"github.com/byteflowing/base/pkg/totp"
+	"github.com/byteflowing/base/pkg/utils/crypto"
 	"github.com/byteflowing/base/pkg/utils/trans"
 	"github.com/byteflowing/base/singleton"
 	configv1 "github.com/byteflowing/proto/gen/go/config/v1"
@@ -29,27 +46,121 @@ import (
 )
 
 type UserService struct {
-	authProviders map[enumsv1.SignInType]auth.Auth
+	defaultTenant *tenantEntry
+	tenants       *tenantRegistry
 	db            *query.Query
+	rdb           *redis.Redis
 	blk           *blocklist.BlockList
 	token         *jwt.Jwt
+	totp          *totp.TOTP
+	geo           *geoService.GeoService
+	msg           *messageService.MessageService
+	ids           *common.IDService
+	queue         *queue.Queue
+	validCache    *validationCache
+	secrets       *crypto.AesGcm
 	cfg           *userv1.UserConfig
 	userv1.UnimplementedUserServiceServer
 }
 
 func NewUserService(cfg *configv1.Config) *UserService {
-	authProviders := newAuthProvider(cfg)
+	newAuth := newAuthFactory(cfg)
+	providers, err := newAuthProvider(cfg.User.Auth, newAuth)
+	if err != nil {
+		panic(err)
+	}
 	orm := singleton.NewDB(cfg.Db)
 	rdb := singleton.NewRDB(cfg.Redis)
 	db := query.Use(orm)
-	blk := blocklist.NewBlockList(cfg.User.KeyPrefix, rdb)
+	var validCache *validationCache
+	if cfg.User.ValidationCache != nil {
+		// 其他实例的吊销只能通过本地黑名单的同步消息得知
+		if cfg.User.LocalBlocklist == nil {
+			panic("user validation cache requires local blocklist config")
+		}
+		validCache = newValidationCache(cfg.User.ValidationCache)
+	}
+	var blk *blocklist.BlockList
+	if local := cfg.User.LocalBlocklist; local != nil {
+		opts := &blocklist.LocalOption{
+			Channel:        local.Channel,
+			MaxEntries:     int(local.MaxEntries),
+			ResyncInterval: local.ResyncInterval.AsDuration(),
+		}
+		if validCache != nil {
+			opts.OnAdd = func(targets []string) { validCache.forget(targets...) }
+		}
+		blk = blocklist.NewLocalBlockList(cfg.User.KeyPrefix, rdb, opts)
+	} else {
+		blk = blocklist.NewBlockList(cfg.User.KeyPrefix, rdb)
+	}
+	// 旧版本的黑名单条目不在新的key下，不迁移的话升级前吊销的token会重新生效
+	if _, err := blk.MigrateLegacyKeys(context.Background()); err != nil {
+		panic(err)
+	}
 	token := jwt.New(cfg.User.Jwt.Issuer, cfg.User.Jwt.SecretKey)
 	u := &UserService{
-		authProviders: authProviders,
-		db:            db,
-		blk:           blk,
-		token:         token,
-		cfg:           cfg.User,
+		defaultTenant: &tenantEntry{
+			accessTtl:      cfg.User.Jwt.AccessTtl.AsDuration(),
+			refreshTtl:     cfg.User.Jwt.RefreshTtl.AsDuration(),
+			providers:      providers,
+			passwordPolicy: cfg.User.PasswordPolicy,
+			claimMappings:  cfg.User.Jwt.ClaimMappings,
+		},
+		tenants:    newTenantRegistry(newAuth),
+		validCache: validCache,
+		db:         db,
+		rdb:        rdb,
+		blk:        blk,
+		token:      token,
+		cfg:        cfg.User,
+	}
+	if cfg.User.Mfa != nil {
+		u.totp = totp.New(&totp.Config{
+			Period: cfg.User.Mfa.Period,
+			Digits: cfg.User.Mfa.Digits,
+			Skew:   cfg.User.Mfa.Skew,
+		})
+	}
+	if cfg.Geo != nil {
+		u.geo = geo.NewOnce(cfg)
+	}
+	if cfg.Message != nil {
+		u.msg = message.NewOnce(cfg)
+	}
+	// 注册、邀请码和SCIM都需要生成id
+	if cfg.GlobalId != nil && cfg.ShortId != nil {
+		u.ids = common.NewIDService(global_id.NewOnce(cfg), singleton.NewShortID(cfg.ShortId))
+	} else if cfg.User.Invite != nil {
+		panic("user invite requires global id and short id config")
+	}
+	if cfg.User.TenantSecretKey != "" {
+		secrets, err := crypto.NewAesGcm(cfg.User.TenantSecretKey)
+		if err != nil {
+			panic(err)
+		}
+		u.secrets = secrets
+	}
+	// TOTP密钥使用tenant_secret_key加密保存
+	if cfg.User.Mfa != nil && u.secrets == nil {
+		panic("user mfa requires tenant secret key config")
+	}
+	if cfg.User.Deletion != nil || cfg.User.Outbox != nil {
+		if cfg.AsynqServer == nil {
+			panic("user deletion and events require asynq server config")
+		}
+		u.queue = queue.NewQueue(rdb, cfg.AsynqServer)
+	}
+	if cfg.User.Deletion != nil {
+		u.queue.RegisterHandler(taskDeleteAccount, u.deleteAccount)
+	}
+	if cfg.User.Outbox != nil {
+		u.startOutboxRelay()
+	} else {
+		logx.Warn("user outbox is not configured, user events will not be published and account merges are disabled")
+	}
+	if cfg.User.SignLogRetention != nil {
+		u.startSignLogRetention()
 	}
 	if cfg.User.AutoMigrate {
 		m := migrate.NewMigrate(orm)
@@ -60,53 +171,188 @@ func NewUserService(cfg *configv1.Config) *UserService {
 	return u
 }
 
+// SignUp 使用手机号或邮箱注册，需要先通过MessageService.SendCaptcha获取验证码，密码可选
+// 账号创建、SIGNED_UP事件以及登录在同一个事务中完成，注册成功后直接返回token
 func (u *UserService) SignUp(ctx context.Context, req *userv1.SignUpReq) (*userv1.SignUpResp, error) {
-	//TODO implement me
-	panic("implement me")
+	if u.ids == nil {
+		return nil, ecode.ErrUnImplemented
+	}
+	phone, email := req.GetPhoneNumber(), common.NormalizeEmail(req.GetEmail())
+	scene := enumsv1.MessageSceneType_MESSAGE_SCENE_TYPE_SIGN_UP
+	var signInType enumsv1.SignInType
+	var identifier string
+	switch {
+	case phone != nil && phone.Number != "":
+		if err := u.verifyPhoneCaptcha(ctx, phone, req.CaptchaToken, req.Captcha, scene); err != nil {
+			return nil, err
+		}
+		signInType = enumsv1.SignInType_SIGN_IN_TYPE_PHONE_CAPTCHA
+		identifier = phone.CountryCode + phone.Number
+	case email != "":
+		if _, err := mail.ParseAddress(email); err != nil {
+			return nil, ecode.ErrParams
+		}
+		if err := u.verifyMailCaptcha(ctx, email, req.CaptchaToken, req.Captcha, scene); err != nil {
+			return nil, err
+		}
+		signInType = enumsv1.SignInType_SIGN_IN_TYPE_EMAIL_CAPTCHA
+		identifier = email
+	default:
+		return nil, ecode.ErrParams
+	}
+	var resp *userv1.SignInResp
+	var alert *deviceAlert
+	err := u.db.Transaction(func(tx *query.Query) error {
+		userAccount, err := u.createSignUpAccount(ctx, tx, req, signInType)
+		if err != nil {
+			return err
+		}
+		user := common.UserModelToUser(userAccount)
+		if err := u.emitEvent(ctx, tx, enumsv1.UserEventType_USER_EVENT_TYPE_SIGNED_UP, user.GetTenantId(), user.GetUid(), &userv1.UserSignedUpEvent{
+			User:       user,
+			SignInType: signInType,
+			Agent:      req.Agent,
+		}); err != nil {
+			return err
+		}
+		if req.InviteCode != "" {
+			if err := u.redeemInviteCode(ctx, tx, user, req.InviteCode); err != nil {
+				return err
+			}
+		}
+		resp, alert, err = u.issueSignIn(ctx, tx, user, signInType, identifier, req.Agent, req.ExtraJwtClaims, false)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	u.sendDeviceAlert(ctx, resp.UserInfo, alert)
+	return &userv1.SignUpResp{
+		AccessToken:  resp.AccessToken,
+		RefreshToken: resp.RefreshToken,
+		UserInfo:     resp.UserInfo,
+	}, nil
 }
 
-func (u *UserService) SignIn(ctx context.Context, req *userv1.SignInReq) (*userv1.SignInResp, error) {
-	provider, err := u.getAuthProvider(req.SignInType)
+// createSignUpAccount 手机号或邮箱在租户下已被使用时返回对应的错误，设置了密码时需要满足租户的密码策略
+func (u *UserService) createSignUpAccount(ctx context.Context, tx *query.Query, req *userv1.SignUpReq, signInType enumsv1.SignInType) (*model.UserAccount, error) {
+	tenant, err := u.getTenant(ctx, tx, req.TenantId)
 	if err != nil {
 		return nil, err
 	}
+	number, err := u.ids.GetShortID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := u.ids.GetGlobalID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	agent := req.GetAgent()
+	if agent == nil {
+		agent = &userv1.Agent{}
+	}
+	userAccount := &model.UserAccount{
+		ID:               id,
+		TenantID:         req.TenantId,
+		Number:           number,
+		Phone:            common.PlaceholderPhone(id),
+		Email:            common.PlaceholderEmail(id),
+		Status:           int16(enumsv1.UserStatus_USER_STATUS_OK),
+		Source:           int16(enumsv1.UserSource_USER_SOURCE_UNSPECIFIED),
+		SignupType:       int16(signInType),
+		RegisterIP:       agent.Ip,
+		RegisterDevice:   agent.Device,
+		RegisterAgent:    agent.Agent,
+		RegisterLocation: common.LocationToString(agent.Location),
+	}
+	accountQ := tx.UserAccount
+	existsErr := ecode.ErrUserEmailExists
+	var conds []gen.Condition
+	if phone := req.GetPhoneNumber(); phone != nil {
+		userAccount.PhoneCountryCode = phone.CountryCode
+		userAccount.Phone = phone.Number
+		userAccount.PhoneVerified = true
+		existsErr = ecode.ErrUserPhoneExists
+		conds = []gen.Condition{accountQ.PhoneCountryCode.Eq(phone.CountryCode), accountQ.Phone.Eq(phone.Number)}
+	} else {
+		userAccount.Email = common.NormalizeEmail(req.GetEmail())
+		userAccount.EmailVerified = true
+		conds = []gen.Condition{accountQ.Email.Eq(userAccount.Email)}
+	}
+	count, err := accountQ.WithContext(ctx).Where(accountQ.TenantID.Eq(req.TenantId)).Where(conds...).Count()
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, existsErr
+	}
+	if req.Password != "" {
+		if err := validatePassword(tenant.passwordPolicy, req.Password); err != nil {
+			return nil, err
+		}
+		hash, err := crypto.DefaultPasswordHasher.HashPassword(req.Password)
+		if err != nil {
+			return nil, err
+		}
+		userAccount.Password = &hash
+		userAccount.PasswordUpdatedAt = trans.Ref(time.Now())
+	}
+	if err := accountQ.WithContext(ctx).Create(userAccount); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, existsErr
+		}
+		return nil, err
+	}
+	return userAccount, nil
+}
+
+func (u *UserService) SignIn(ctx context.Context, req *userv1.SignInReq) (*userv1.SignInResp, error) {
 	var resp *userv1.SignInResp
-	err = u.db.Transaction(func(tx *query.Query) error {
+	var alert *deviceAlert
+	err := u.db.Transaction(func(tx *query.Query) error {
+		provider, err := u.getAuthProvider(ctx, tx, req.TenantId, req.SignInType)
+		if err != nil {
+			return err
+		}
 		result, err := provider.Authenticate(ctx, req, tx)
 		if err != nil {
 			return err
 		}
-		user := result.User
-		accessToken, refreshToken, err := u.genToken(user, req.ExtraJwtClaims)
-		agent := req.Agent
-		if agent == nil {
-			agent = &userv1.Agent{}
+		if result.Created {
+			if err := u.emitEvent(ctx, tx, enumsv1.UserEventType_USER_EVENT_TYPE_SIGNED_UP, result.User.GetTenantId(), result.User.GetUid(), &userv1.UserSignedUpEvent{
+				User:       result.User,
+				SignInType: req.SignInType,
+				Agent:      req.Agent,
+			}); err != nil {
+				return err
+			}
+			// 邀请码只在首次登录创建账号时生效
+			if req.InviteCode != "" {
+				if err := u.redeemInviteCode(ctx, tx, result.User, req.InviteCode); err != nil {
+					return err
+				}
+			}
 		}
-		if err := tx.UserSignLog.WithContext(ctx).Create(&model.UserSignLog{
-			TenantID:         user.GetTenantId(),
-			UID:              user.GetUid(),
-			Type:             int16(req.SignInType),
-			Status:           int16(user.GetStatus()),
-			Identifier:       result.Identifier,
-			IP:               agent.Ip,
-			Location:         common.LocationToString(agent.Location),
-			Agent:            agent.Agent,
-			Device:           agent.Device,
-			AccessJti:        accessToken.Jti,
-			RefreshJti:       refreshToken.Jti,
-			AccessExpiredAt:  trans.Ref(accessToken.Exp),
-			RefreshExpiredAt: trans.Ref(refreshToken.Exp),
-		}); err != nil {
+		mfaToken, err := u.checkMfa(ctx, tx, result, req.SignInType)
+		if err != nil {
 			return err
 		}
-		resp = &userv1.SignInResp{
-			AccessToken:  accessToken.Token,
-			RefreshToken: refreshToken.Token,
-			UserInfo:     user,
+		if mfaToken != "" {
+			resp = &userv1.SignInResp{
+				MfaRequired: true,
+				MfaToken:    mfaToken,
+			}
+			return nil
 		}
-		return nil
+		resp, alert, err = u.issueSignIn(ctx, tx, result.User, req.SignInType, result.Identifier, req.Agent, req.ExtraJwtClaims, false)
+		return err
 	})
-	return resp, err
+	if err != nil {
+		return nil, err
+	}
+	u.sendDeviceAlert(ctx, resp.UserInfo, alert)
+	return resp, nil
 }
 
 func (u *UserService) SignOut(ctx context.Context, req *userv1.SignOutReq) (*userv1.SignOutResp, error) {
@@ -118,10 +364,17 @@ func (u *UserService) SignOut(ctx context.Context, req *userv1.SignOutReq) (*use
 	if accessJti == "" {
 		return nil, ecode.ErrUserTokenInvalid
 	}
+	// 模拟登录的token没有登录日志和refresh token，直接加入黑名单
+	if common.GetTokenActorUID(claims) != 0 {
+		if err := u.addJtiToBlkByClaims(ctx, claims); err != nil {
+			return nil, err
+		}
+		return &userv1.SignOutResp{}, nil
+	}
 	logQ := u.db.UserSignLog
 	logModel, err := logQ.WithContext(ctx).Where(
 		logQ.AccessJti.Eq(accessJti),
-		logQ.Status.Neq(int16(enumsv1.SignInStatus_SIGN_IN_STATUS_OK)),
+		logQ.Status.Eq(int16(enumsv1.SignInStatus_SIGN_IN_STATUS_OK)),
 	).First()
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -135,16 +388,38 @@ func (u *UserService) SignOut(ctx context.Context, req *userv1.SignOutReq) (*use
 	if err := u.addJtiToBlkByLog(ctx, logModel); err != nil {
 		return nil, err
 	}
+	// 第三方凭证按身份保存，同一身份在其他设备上的登录仍在使用时不能撤销
+	active, err := u.hasActiveSignIn(ctx, logModel)
+	if err != nil {
+		logx.CtxWarn(ctx, "check active sign in failed", zap.Int64("uid", logModel.UID), zap.Error(err))
+		return &userv1.SignOutResp{}, nil
+	}
+	if !active {
+		u.revokeThirdParty(ctx, logModel.TenantID, enumsv1.SignInType(logModel.Type), logModel.Identifier)
+	}
 	return &userv1.SignOutResp{}, nil
 }
 
+// hasActiveSignIn 同一身份是否还有其他未退出且refresh token未过期的登录
+func (u *UserService) hasActiveSignIn(ctx context.Context, logModel *model.UserSignLog) (bool, error) {
+	logQ := u.db.UserSignLog
+	count, err := logQ.WithContext(ctx).Where(
+		logQ.TenantID.Eq(logModel.TenantID),
+		logQ.Type.Eq(logModel.Type),
+		logQ.Identifier.Eq(logModel.Identifier),
+		logQ.Status.Eq(int16(enumsv1.SignInStatus_SIGN_IN_STATUS_OK)),
+		logQ.RefreshExpiredAt.Gt(time.Now()),
+		logQ.ID.Neq(logModel.ID),
+	).Count()
+	return count > 0, err
+}
+
 func (u *UserService) ValidateToken(ctx context.Context, req *userv1.ValidateTokenReq) (*userv1.ValidateTokenResp, error) {
 	claims, err := u.token.Parse(req.Token, req.Type.String())
 	if err != nil {
 		return nil, err
 	}
-	jwtClaims := common.ClaimsToJwtClaims(claims, req.ExtraKey)
-	blocked, err := u.blk.Exists(ctx, jwtClaims.Jti)
+	blocked, err := u.isJtiBlocked(ctx, claims)
 	if err != nil {
 		return nil, err
 	}
@@ -153,7 +428,7 @@ func (u *UserService) ValidateToken(ctx context.Context, req *userv1.ValidateTok
 	}
 	return &userv1.ValidateTokenResp{
 		Type:   req.Type,
-		Claims: jwtClaims,
+		Claims: common.ClaimsToJwtClaims(claims, req.ExtraKey),
 	}, nil
 }
 
@@ -173,27 +448,19 @@ func (u *UserService) RefreshToken(ctx context.Context, req *userv1.RefreshToken
 	if blocked {
 		return nil, ecode.ErrUserTokenInvalid
 	}
-	sub, err := claims.GetSubject()
+	uid, err := common.GetJwtUID(claims)
 	if err != nil {
 		return nil, err
 	}
-	uid, err := strconv.ParseInt(sub, 10, 64)
+	userAccount, err := u.getUserAccount(ctx, u.db, uid)
 	if err != nil {
 		return nil, err
 	}
-	accountQ := u.db.UserAccount
-	userAccount, err := accountQ.WithContext(ctx).Where(accountQ.ID.Eq(uid)).First()
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ecode.ErrUserNotFound
-		}
-		return nil, err
-	}
 	if !common.IsUserValid(userAccount.Status) {
 		return nil, ecode.ErrUserDisabled
 	}
 	user := common.UserModelToUser(userAccount)
-	accessToken, refreshToken, err := u.genToken(user, req.ExtraJwtClaims)
+	accessToken, refreshToken, err := u.genToken(ctx, u.db, user, req.ExtraJwtClaims)
 	if err != nil {
 		return nil, err
 	}
@@ -218,9 +485,161 @@ func (u *UserService) RefreshToken(ctx context.Context, req *userv1.RefreshToken
 	}, nil
 }
 
+// issueSignIn 签发token并记录登录日志
+// 返回的登录提醒需要在事务提交后通过sendDeviceAlert发送，避免在事务中调用外部服务
+func (u *UserService) issueSignIn(
+	ctx context.Context,
+	tx *query.Query,
+	user *userv1.User,
+	signInType enumsv1.SignInType,
+	identifier string,
+	agent *userv1.Agent,
+	extra map[string]string,
+	mfaPassed bool,
+) (*userv1.SignInResp, *deviceAlert, error) {
+	accessToken, refreshToken, err := u.genToken(ctx, tx, user, extra)
+	if err != nil {
+		return nil, nil, err
+	}
+	if agent == nil {
+		agent = &userv1.Agent{}
+	}
+	logModel := &model.UserSignLog{
+		TenantID:         user.GetTenantId(),
+		UID:              user.GetUid(),
+		Type:             int16(signInType),
+		Status:           int16(enumsv1.SignInStatus_SIGN_IN_STATUS_OK),
+		Identifier:       identifier,
+		IP:               agent.Ip,
+		Location:         common.LocationToString(agent.Location),
+		Agent:            agent.Agent,
+		Device:           agent.Device,
+		AccessJti:        accessToken.Jti,
+		RefreshJti:       refreshToken.Jti,
+		AccessExpiredAt:  trans.Ref(accessToken.Exp),
+		RefreshExpiredAt: trans.Ref(refreshToken.Exp),
+	}
+	if err := tx.UserSignLog.WithContext(ctx).Create(logModel); err != nil {
+		return nil, nil, err
+	}
+	alert, err := u.trackDevice(ctx, tx, logModel, mfaPassed)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := u.emitEvent(ctx, tx, enumsv1.UserEventType_USER_EVENT_TYPE_SIGNED_IN, user.GetTenantId(), user.GetUid(), &userv1.UserSignedInEvent{
+		SignInType: signInType,
+		Agent:      agent,
+	}); err != nil {
+		return nil, nil, err
+	}
+	mustChange, err := u.mustChangePassword(ctx, tx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &userv1.SignInResp{
+		AccessToken:        accessToken.Token,
+		RefreshToken:       refreshToken.Token,
+		UserInfo:           user,
+		MustChangePassword: mustChange,
+	}, alert, nil
+}
+
+// parseAccessToken 解析access token并检查是否已被吊销，返回token对应的uid
+func (u *UserService) parseAccessToken(ctx context.Context, token string) (uid int64, claims jwtv5.MapClaims, err error) {
+	claims, err = u.token.Parse(token, enumsv1.TokenType_TOKEN_TYPE_ACCESS.String())
+	if err != nil {
+		return 0, nil, err
+	}
+	jti := common.GetJwtJti(claims)
+	if jti == "" {
+		return 0, nil, ecode.ErrUserTokenInvalid
+	}
+	blocked, err := u.blk.Exists(ctx, jti)
+	if err != nil {
+		return 0, nil, err
+	}
+	if blocked {
+		return 0, nil, ecode.ErrUserTokenInvalid
+	}
+	uid, err = common.GetJwtUID(claims)
+	if err != nil {
+		return 0, nil, ecode.ErrUserTokenInvalid
+	}
+	return uid, claims, nil
+}
+
+func (u *UserService) getUserAccount(ctx context.Context, tx *query.Query, uid int64) (*model.UserAccount, error) {
+	accountQ := tx.UserAccount
+	userAccount, err := accountQ.WithContext(ctx).Where(accountQ.ID.Eq(uid)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ecode.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return userAccount, nil
+}
+
 func (u *UserService) addJtiToBlkByLog(ctx context.Context, logModel *model.UserSignLog) error {
-	var items []*blocklist.BlockItem
+	return u.addBlockItems(ctx, u.getBlockItemsByLog(logModel, time.Now()))
+}
+
+// addBlockItems 加入黑名单并清除本实例的validation cache
+func (u *UserService) addBlockItems(ctx context.Context, items []*blocklist.BlockItem) error {
+	for _, item := range items {
+		u.validCache.forget(item.Target)
+	}
+	return u.blk.BatchAdd(ctx, items)
+}
+
+// revocation 收集事务中吊销的token，事务提交后再通过addBlockItems加入黑名单
+// 事务回滚时会话没有被吊销，token也不能失效
+type revocation struct {
+	items []*blocklist.BlockItem
+}
+
+func (r *revocation) add(items ...*blocklist.BlockItem) {
+	r.items = append(r.items, items...)
+}
+
+// revokeSessions 吊销用户所有未过期的登录会话和模拟登录token，会话对应的token收集到rv中
+func (u *UserService) revokeSessions(ctx context.Context, tx *query.Query, rv *revocation, uid int64, status enumsv1.SignInStatus) error {
+	return u.revokeOtherSessions(ctx, tx, rv, uid, status, "")
+}
+
+// revokeOtherSessions 和revokeSessions相同，但保留access token为keepAccessJti的会话
+func (u *UserService) revokeOtherSessions(ctx context.Context, tx *query.Query, rv *revocation, uid int64, status enumsv1.SignInStatus, keepAccessJti string) error {
 	now := time.Now()
+	if err := u.revokeImpersonations(ctx, tx, rv, uid, now); err != nil {
+		return err
+	}
+	logQ := tx.UserSignLog
+	conds := []gen.Condition{
+		logQ.UID.Eq(uid),
+		logQ.Status.Eq(int16(enumsv1.SignInStatus_SIGN_IN_STATUS_OK)),
+		logQ.RefreshExpiredAt.Gt(now),
+	}
+	if keepAccessJti != "" {
+		conds = append(conds, logQ.AccessJti.Neq(keepAccessJti))
+	}
+	logs, err := logQ.WithContext(ctx).Where(conds...).Find()
+	if err != nil {
+		return err
+	}
+	if len(logs) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(logs))
+	for _, l := range logs {
+		ids = append(ids, l.ID)
+		rv.add(u.getBlockItemsByLog(l, now)...)
+	}
+	_, err = logQ.WithContext(ctx).Where(logQ.ID.In(ids...)).Update(logQ.Status, int16(status))
+	return err
+}
+
+func (u *UserService) getBlockItemsByLog(logModel *model.UserSignLog, now time.Time) []*blocklist.BlockItem {
+	var items []*blocklist.BlockItem
 	if logModel.AccessExpiredAt != nil {
 		ttl := logModel.AccessExpiredAt.Sub(now)
 		if ttl > 0 {
@@ -239,7 +658,7 @@ func (u *UserService) addJtiToBlkByLog(ctx context.Context, logModel *model.User
 			})
 		}
 	}
-	return u.blk.BatchAdd(ctx, items)
+	return items
 }
 
 func (u *UserService) addJtiToBlkByClaims(ctx context.Context, claims jwtv5.MapClaims) error {
@@ -252,23 +671,24 @@ func (u *UserService) addJtiToBlkByClaims(ctx context.Context, claims jwtv5.MapC
 		return nil
 	}
 	jti := common.GetJwtJti(claims)
+	u.validCache.forget(jti)
 	return u.blk.Add(ctx, jti, ttl)
 }
 
-func (u *UserService) genToken(user *userv1.User, extra map[string]string) (accessToken, refreshToken *jwt.Token, err error) {
-	extraClaims := map[string]any{
-		common.JwtTenantIDKey: user.GetTenantId(),
-		common.JwtNumberKey:   user.GetNumber(),
-		common.JwtTypeKey:     user.GetUserType(),
-		common.JwtLevelKey:    user.GetUserLevel(),
+// genToken 签发access token和refresh token，有效期使用用户所在租户的配置
+func (u *UserService) genToken(ctx context.Context, tx *query.Query, user *userv1.User, extra map[string]string) (accessToken, refreshToken *jwt.Token, err error) {
+	tenant, err := u.getTenant(ctx, tx, user.GetTenantId())
+	if err != nil {
+		return
 	}
-	for k, v := range extra {
-		extraClaims[k] = v
+	extraClaims, err := u.userClaims(ctx, tx, tenant, user, extra)
+	if err != nil {
+		return
 	}
 	if accessToken, err = u.token.Generate(
 		strconv.FormatInt(user.Uid, 10),
 		enumsv1.TokenType_TOKEN_TYPE_ACCESS.String(),
-		u.cfg.Jwt.AccessTtl.AsDuration(),
+		tenant.accessTtl,
 		extraClaims,
 	); err != nil {
 		return
@@ -276,7 +696,7 @@ func (u *UserService) genToken(user *userv1.User, extra map[string]string) (acce
 	if refreshToken, err = u.token.Generate(
 		strconv.FormatInt(user.Uid, 10),
 		enumsv1.TokenType_TOKEN_TYPE_REFRESH.String(),
-		u.cfg.Jwt.RefreshTtl.AsDuration(),
+		tenant.refreshTtl,
 		extraClaims,
 	); err != nil {
 		return
@@ -284,29 +704,118 @@ func (u *UserService) genToken(user *userv1.User, extra map[string]string) (acce
 	return
 }
 
-func (u *UserService) getAuthProvider(typ enumsv1.SignInType) (auth.Auth, error) {
-	provider, ok := u.authProviders[typ]
+// userClaims 用户token中的自定义claims
+func (u *UserService) userClaims(ctx context.Context, tx *query.Query, tenant *tenantEntry, user *userv1.User, extra map[string]string) (map[string]any, error) {
+	extraClaims := map[string]any{
+		common.JwtTenantIDKey: user.GetTenantId(),
+		common.JwtNumberKey:   user.GetNumber(),
+		common.JwtTypeKey:     user.GetUserType(),
+		common.JwtLevelKey:    user.GetUserLevel(),
+	}
+	// 请求中的自定义claims不能覆盖服务端写入的claim，e.g. tenant_id、roles
+	for k, v := range extra {
+		if common.IsReservedClaim(k) {
+			continue
+		}
+		extraClaims[k] = v
+	}
+	// 从账号数据映射的claim以服务端数据为准，覆盖请求中的同名claim
+	if len(tenant.claimMappings) > 0 {
+		userAccount, err := u.getUserAccount(ctx, tx, user.GetUid())
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range common.MapUserClaims(userAccount, tenant.claimMappings) {
+			extraClaims[k] = v
+		}
+	}
+	// 角色和游客scope在extra之后写入，避免被请求中的自定义claims覆盖
+	roles, err := u.getUserRoleNames(ctx, tx, user.GetUid())
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) > 0 {
+		extraClaims[common.JwtRolesKey] = roles
+	}
+	if isGuest(user) {
+		extraClaims[common.JwtScopeKey] = u.guestScope()
+	}
+	return extraClaims, nil
+}
+
+// getAuthProvider 获取租户开放的登录方式
+func (u *UserService) getAuthProvider(ctx context.Context, tx *query.Query, tenantID string, typ enumsv1.SignInType) (auth.Auth, error) {
+	tenant, err := u.getTenant(ctx, tx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	provider, ok := tenant.providers[typ]
 	if !ok {
-		return nil, fmt.Errorf("unknown sign-in type %s", typ)
+		return nil, ecode.ErrUserAuthInvalid
 	}
 	return provider, nil
 }
 
-func newAuthProvider(config *configv1.Config) map[enumsv1.SignInType]auth.Auth {
-	authMap := make(map[enumsv1.SignInType]auth.Auth, len(config.User.Auth))
-	for _, v := range config.User.Auth {
+func newAuthProvider(configs []*userv1.AuthConfig, newAuth func(cfg *userv1.AuthConfig) (auth.Auth, error)) (map[enumsv1.SignInType]auth.Auth, error) {
+	authMap := make(map[enumsv1.SignInType]auth.Auth, len(configs))
+	for _, v := range configs {
+		provider, err := newAuth(v)
+		if err != nil {
+			return nil, fmt.Errorf("create %s auth provider: %w", v.Type, err)
+		}
+		if provider != nil {
+			authMap[v.Type] = provider
+		}
+	}
+	return authMap, nil
+}
+
+// newAuthFactory 根据认证配置创建认证方式，配置不完整或不支持的类型返回nil，配置有误时返回错误
+func newAuthFactory(config *configv1.Config) func(cfg *userv1.AuthConfig) (auth.Auth, error) {
+	return func(v *userv1.AuthConfig) (auth.Auth, error) {
 		switch v.Type {
 		case enumsv1.SignInType_SIGN_IN_TYPE_WECHAT_MINI:
-			authMap[v.Type] = tencent.NewWechatManager(v.Wechat)
+			if v.Wechat == nil {
+				return nil, nil
+			}
+			return tencent.NewWechatManager(v.Wechat), nil
 		case enumsv1.SignInType_SIGN_IN_TYPE_HUAWEI:
+			if v.Huawei == nil {
+				return nil, nil
+			}
 			shortID := common.NewIDService(global_id.NewOnce(config), singleton.NewShortID(config.ShortId))
-			authMap[v.Type] = huawei.NewAccountManager(
+			return huawei.NewAccountManager(
 				config.User.KeyPrefix,
 				singleton.NewRDB(config.Redis),
 				shortID,
 				v.Huawei,
-			)
+			), nil
+		case enumsv1.SignInType_SIGN_IN_TYPE_APPLE:
+			if v.Apple == nil {
+				return nil, nil
+			}
+			shortID := common.NewIDService(global_id.NewOnce(config), singleton.NewShortID(config.ShortId))
+			return apple.NewAccountManager(shortID, v.Apple), nil
+		case enumsv1.SignInType_SIGN_IN_TYPE_GUEST:
+			shortID := common.NewIDService(global_id.NewOnce(config), singleton.NewShortID(config.ShortId))
+			return guest.NewAccountManager(shortID), nil
+		case enumsv1.SignInType_SIGN_IN_TYPE_OIDC:
+			if v.Oidc == nil {
+				return nil, nil
+			}
+			shortID := common.NewIDService(global_id.NewOnce(config), singleton.NewShortID(config.ShortId))
+			return oidc.NewManager(shortID, v.Oidc), nil
+		case enumsv1.SignInType_SIGN_IN_TYPE_LDAP:
+			if v.Ldap == nil {
+				return nil, nil
+			}
+			shortID := common.NewIDService(global_id.NewOnce(config), singleton.NewShortID(config.ShortId))
+			manager, err := ldap.NewManager(shortID, v.Ldap)
+			if err != nil {
+				return nil, err
+			}
+			return manager, nil
 		}
+		return nil, nil
 	}
-	return authMap
 }
@@ -59,6 +59,11 @@ func (s *Server) Stop() {
 	s.server.GracefulStop()
 }
 
+// Attach 附加和grpc server一起启动和停止的服务，e.g. http server
+func (s *Server) Attach(handler signalx.SignalHandler) {
+	s.signal.Add(handler)
+}
+
 func (s *Server) Spin() {
 	s.signal.Add(singleton.GetStarterMgr())
 	s.signal.Add(s)
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/byteflowing/base/pkg/logx"
+	configv1 "github.com/byteflowing/proto/gen/go/config/v1"
+)
+
+// HttpRegisterFn 把http接口挂载到mux上，e.g. SCIM
+type HttpRegisterFn func(cfg *configv1.Config, mux *http.ServeMux)
+
+// HttpServer 和grpc server监听不同的地址，随grpc server一起启动和停止
+type HttpServer struct {
+	cfg       *configv1.Config
+	server    *http.Server
+	mux       *http.ServeMux
+	registers []HttpRegisterFn
+}
+
+func NewHttpServer(cfg *configv1.Config, registers []HttpRegisterFn) *HttpServer {
+	mux := http.NewServeMux()
+	return &HttpServer{
+		cfg:       cfg,
+		server:    &http.Server{Addr: cfg.Server.HttpAddr, Handler: mux},
+		mux:       mux,
+		registers: registers,
+	}
+}
+
+func (s *HttpServer) Start() {
+	for _, register := range s.registers {
+		register(s.cfg, s.mux)
+	}
+	logx.Info("http server started", zap.String("addr", s.cfg.Server.HttpAddr))
+	if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logx.Fatal("http server failed to serve", zap.Error(err))
+	}
+	logx.Info("http server stopped", zap.String("addr", s.cfg.Server.HttpAddr))
+}
+
+func (s *HttpServer) Stop() {
+	logx.Info("http graceful stop method is called, so stopping...", zap.String("addr", s.cfg.Server.HttpAddr))
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.Server.WaitForShutdown.AsDuration())
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		logx.Error("http server shutdown failed", zap.Error(err))
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"flag"
+	"net/http"
 
 	"google.golang.org/grpc"
 
@@ -10,6 +11,8 @@ import (
 	"github.com/byteflowing/base/app/maps"
 	"github.com/byteflowing/base/app/message"
 	"github.com/byteflowing/base/app/user"
+	"github.com/byteflowing/base/app/user/introspect"
+	"github.com/byteflowing/base/app/user/scim"
 	"github.com/byteflowing/base/pkg/logx"
 	"github.com/byteflowing/base/pkg/utils/slicex"
 	"github.com/byteflowing/base/singleton"
@@ -29,6 +32,9 @@ func main() {
 	logx.Init(cfg.Log)
 
 	server := NewGrpcServer(cfg, getServices(cfg.Services))
+	if cfg.Server.HttpAddr != "" {
+		server.Attach(NewHttpServer(cfg, getHttpServices(cfg.Services)))
+	}
 	server.Spin()
 	_ = logx.Sync()
 }
@@ -58,6 +64,13 @@ func RegisterUserService(c *configv1.Config, grpcServer *grpc.Server) {
 	userv1.RegisterUserServiceServer(grpcServer, srv)
 }
 
+// RegisterUserHttp 挂载用户服务的SCIM和token自省接口
+func RegisterUserHttp(c *configv1.Config, mux *http.ServeMux) {
+	srv := user.NewOnce(c)
+	mux.Handle(scim.BasePath+"/", http.StripPrefix(scim.BasePath, scim.NewHandler(srv)))
+	mux.Handle(introspect.Path, introspect.NewHandler(srv))
+}
+
 func getServices(ss []enumsv1.SupportedService) []RegisterFn {
 	var fn []RegisterFn
 	ss = slicex.Unique(ss)
@@ -77,3 +90,15 @@ func getServices(ss []enumsv1.SupportedService) []RegisterFn {
 	}
 	return fn
 }
+
+func getHttpServices(ss []enumsv1.SupportedService) []HttpRegisterFn {
+	var fn []HttpRegisterFn
+	ss = slicex.Unique(ss)
+	for _, s := range ss {
+		switch s {
+		case enumsv1.SupportedService_SUPPORTED_SERVICE_USER:
+			fn = append(fn, RegisterUserHttp)
+		}
+	}
+	return fn
+}
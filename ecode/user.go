@@ -6,8 +6,59 @@ import (
 )
 
 var (
-	ErrUserAuthInvalid  = status.Error(codes.PermissionDenied, "ERR_USER_AUTH_INVALID")  // 当前认证不可用
-	ErrUserTokenInvalid = status.Error(codes.PermissionDenied, "ERR_USER_TOKEN_INVALID") // 登录信息不可用
-	ErrUserDisabled     = status.Error(codes.PermissionDenied, "ERR_USER_DISABLED")      // 用户被禁用
-	ErrUserNotFound     = status.Error(codes.NotFound, "ERR_USER_NOT_FOUND")             // 用户不存在
+	ErrUserAuthInvalid             = status.Error(codes.PermissionDenied, "ERR_USER_AUTH_INVALID")                // 当前认证不可用
+	ErrUserTokenInvalid            = status.Error(codes.PermissionDenied, "ERR_USER_TOKEN_INVALID")               // 登录信息不可用
+	ErrUserDisabled                = status.Error(codes.PermissionDenied, "ERR_USER_DISABLED")                    // 用户被禁用
+	ErrUserNotFound                = status.Error(codes.NotFound, "ERR_USER_NOT_FOUND")                           // 用户不存在
+	ErrUserMfaUnsupported          = status.Error(codes.Unimplemented, "ERR_USER_MFA_UNSUPPORTED")                // 未开启两步验证功能
+	ErrUserMfaNotEnabled           = status.Error(codes.FailedPrecondition, "ERR_USER_MFA_NOT_ENABLED")           // 用户未开启两步验证
+	ErrUserMfaAlreadyEnabled       = status.Error(codes.AlreadyExists, "ERR_USER_MFA_ALREADY_ENABLED")            // 用户已开启两步验证
+	ErrUserMfaNotEnrolled          = status.Error(codes.FailedPrecondition, "ERR_USER_MFA_NOT_ENROLLED")          // 未发起两步验证绑定
+	ErrUserMfaCodeInvalid          = status.Error(codes.InvalidArgument, "ERR_USER_MFA_CODE_INVALID")             // 两步验证码错误
+	ErrUserRegionInvalid           = status.Error(codes.InvalidArgument, "ERR_USER_REGION_INVALID")               // 地区信息不正确
+	ErrUserExtInvalid              = status.Error(codes.InvalidArgument, "ERR_USER_EXT_INVALID")                  // 扩展信息不是合法的json
+	ErrUserDeletionPending         = status.Error(codes.AlreadyExists, "ERR_USER_DELETION_PENDING")               // 已申请注销
+	ErrUserDeletionNotFound        = status.Error(codes.NotFound, "ERR_USER_DELETION_NOT_FOUND")                  // 注销申请不存在
+	ErrUserTenantNotFound          = status.Error(codes.NotFound, "ERR_USER_TENANT_NOT_FOUND")                    // 租户不存在
+	ErrUserTenantDisabled          = status.Error(codes.PermissionDenied, "ERR_USER_TENANT_DISABLED")             // 租户被禁用
+	ErrUserTenantExists            = status.Error(codes.AlreadyExists, "ERR_USER_TENANT_EXISTS")                  // 租户已存在
+	ErrUserTenantSecretKeyMissing  = status.Error(codes.FailedPrecondition, "ERR_USER_TENANT_SECRET_KEY_MISSING") // 未配置租户密钥的加密key
+	ErrUserRoleNotFound            = status.Error(codes.NotFound, "ERR_USER_ROLE_NOT_FOUND")                      // 角色不存在
+	ErrUserRoleExists              = status.Error(codes.AlreadyExists, "ERR_USER_ROLE_EXISTS")                    // 角色已存在
+	ErrUserPermissionDenied        = status.Error(codes.PermissionDenied, "ERR_USER_PERMISSION_DENIED")           // 没有权限
+	ErrUserSignInRisky             = status.Error(codes.PermissionDenied, "ERR_USER_SIGN_IN_RISKY")               // 登录存在风险
+	ErrUserAuthNotLinked           = status.Error(codes.NotFound, "ERR_USER_AUTH_NOT_LINKED")                     // 未绑定该登录方式
+	ErrUserAuthLastOne             = status.Error(codes.FailedPrecondition, "ERR_USER_AUTH_LAST_ONE")             // 不能解绑唯一的登录方式
+	ErrUserAuthAlreadyLinked       = status.Error(codes.AlreadyExists, "ERR_USER_AUTH_ALREADY_LINKED")            // 该登录方式已被绑定
+	ErrUserServiceAccountNotFound  = status.Error(codes.NotFound, "ERR_USER_SERVICE_ACCOUNT_NOT_FOUND")           // 服务账号不存在
+	ErrUserServiceAccountExists    = status.Error(codes.AlreadyExists, "ERR_USER_SERVICE_ACCOUNT_EXISTS")         // 服务账号已存在
+	ErrUserServiceAccountDisabled  = status.Error(codes.PermissionDenied, "ERR_USER_SERVICE_ACCOUNT_DISABLED")    // 服务账号已禁用
+	ErrUserApiKeyNotFound          = status.Error(codes.NotFound, "ERR_USER_API_KEY_NOT_FOUND")                   // API key不存在
+	ErrUserApiKeyInvalid           = status.Error(codes.Unauthenticated, "ERR_USER_API_KEY_INVALID")              // API key无效
+	ErrUserApiKeyExpired           = status.Error(codes.Unauthenticated, "ERR_USER_API_KEY_EXPIRED")              // API key已过期
+	ErrUserScopeNotAllowed         = status.Error(codes.PermissionDenied, "ERR_USER_SCOPE_NOT_ALLOWED")           // 请求的scope超出授权范围
+	ErrUserDeviceNotFound          = status.Error(codes.NotFound, "ERR_USER_DEVICE_NOT_FOUND")                    // 设备不存在
+	ErrUserNotGuest                = status.Error(codes.FailedPrecondition, "ERR_USER_NOT_GUEST")                 // 不是游客账号
+	ErrUserGuestNotAllowed         = status.Error(codes.PermissionDenied, "ERR_USER_GUEST_NOT_ALLOWED")           // 游客账号不能进行该操作
+	ErrUserCaptchaInvalid          = status.Error(codes.InvalidArgument, "ERR_USER_CAPTCHA_INVALID")              // 验证码错误或已过期
+	ErrUserPhoneExists             = status.Error(codes.AlreadyExists, "ERR_USER_PHONE_EXISTS")                   // 手机号已被其他账号使用
+	ErrUserEmailExists             = status.Error(codes.AlreadyExists, "ERR_USER_EMAIL_EXISTS")                   // 邮箱已被其他账号使用
+	ErrUserPasswordInvalid         = status.Error(codes.InvalidArgument, "ERR_USER_PASSWORD_INVALID")             // 密码错误
+	ErrUserPasswordTooShort        = status.Error(codes.InvalidArgument, "ERR_USER_PASSWORD_TOO_SHORT")           // 密码长度不足
+	ErrUserPasswordTooSimple       = status.Error(codes.InvalidArgument, "ERR_USER_PASSWORD_TOO_SIMPLE")          // 密码缺少要求的字符类型
+	ErrUserPasswordCommon          = status.Error(codes.InvalidArgument, "ERR_USER_PASSWORD_COMMON")              // 密码过于常见或已泄露
+	ErrUserPasswordReused          = status.Error(codes.InvalidArgument, "ERR_USER_PASSWORD_REUSED")              // 不能使用最近用过的密码
+	ErrUserMergeConflict           = status.Error(codes.FailedPrecondition, "ERR_USER_MERGE_CONFLICT")            // 账号之间存在冲突，不能合并
+	ErrUserInviteCodeNotFound      = status.Error(codes.NotFound, "ERR_USER_INVITE_CODE_NOT_FOUND")               // 邀请码不存在
+	ErrUserInviteCodeInvalid       = status.Error(codes.InvalidArgument, "ERR_USER_INVITE_CODE_INVALID")          // 邀请码无效
+	ErrUserInviteCodeExpired       = status.Error(codes.FailedPrecondition, "ERR_USER_INVITE_CODE_EXPIRED")       // 邀请码已过期
+	ErrUserInviteCodeExhausted     = status.Error(codes.ResourceExhausted, "ERR_USER_INVITE_CODE_EXHAUSTED")      // 邀请码使用次数已达上限
+	ErrUserInviteCodeLimit         = status.Error(codes.ResourceExhausted, "ERR_USER_INVITE_CODE_LIMIT")          // 可用的邀请码数量已达上限
+	ErrUserScimTokenNotFound       = status.Error(codes.NotFound, "ERR_USER_SCIM_TOKEN_NOT_FOUND")                // SCIM token不存在
+	ErrUserScimTokenInvalid        = status.Error(codes.Unauthenticated, "ERR_USER_SCIM_TOKEN_INVALID")           // SCIM token无效或已过期
+	ErrUserScimUserExists          = status.Error(codes.AlreadyExists, "ERR_USER_SCIM_USER_EXISTS")               // userName已被其他用户使用
+	ErrUserImpersonationNotAllowed = status.Error(codes.PermissionDenied, "ERR_USER_IMPERSONATION_NOT_ALLOWED")   // 模拟登录的token不能进行该操作
+	ErrUserImpersonateSelf         = status.Error(codes.InvalidArgument, "ERR_USER_IMPERSONATE_SELF")             // 不能模拟登录自己的账号
+	ErrUserEventsDisabled          = status.Error(codes.FailedPrecondition, "ERR_USER_EVENTS_DISABLED")           // 未开启outbox，依赖事件的功能不可用
+	ErrUserNotDisabled             = status.Error(codes.FailedPrecondition, "ERR_USER_NOT_DISABLED")              // 只能启用被禁用的账号
 )
@@ -4,9 +4,12 @@ go 1.25.1
 
 require (
 	buf.build/go/protovalidate v0.14.0
+	github.com/alicebob/miniredis/v2 v2.35.0
 	github.com/bytedance/gopkg v0.1.3
 	github.com/byteflowing/go-common v1.0.1-0.20250912143503-7d9ab0874afd
 	github.com/byteflowing/proto v0.0.0-20250912141329-1e01347ef3d5
+	github.com/go-asn1-ber/asn1-ber v1.5.8-0.20250403174932-29230038a667
+	github.com/go-ldap/ldap/v3 v3.4.11
 	github.com/golang-jwt/jwt/v5 v5.3.0
 	github.com/google/wire v0.7.0
 	github.com/grpc-ecosystem/go-grpc-middleware/v2 v2.3.2
@@ -24,6 +27,7 @@ require (
 	buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go v1.36.8-20250717185734-6c6e0d3c608e.1 // indirect
 	cel.dev/expr v0.24.0 // indirect
 	filippo.io/edwards25519 v1.1.0 // indirect
+	github.com/Azure/go-ntlmssp v0.0.0-20221128193559-754e69321358 // indirect
 	github.com/alibabacloud-go/alibabacloud-gateway-spi v0.0.5 // indirect
 	github.com/alibabacloud-go/darabonba-openapi/v2 v2.1.11 // indirect
 	github.com/alibabacloud-go/debug v1.0.1 // indirect
@@ -120,6 +124,7 @@ require (
 	github.com/volcengine/ve-tos-golang-sdk/v2 v2.7.21 // indirect
 	github.com/volcengine/volc-sdk-golang v1.0.219 // indirect
 	github.com/wneessen/go-mail v0.6.2 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
 	go.etcd.io/etcd/api/v3 v3.5.15 // indirect
 	go.etcd.io/etcd/client/pkg/v3 v3.5.15 // indirect
 	go.etcd.io/etcd/client/v3 v3.5.15 // indirect
@@ -10,6 +10,10 @@ import (
 	redisv9 "github.com/redis/go-redis/v9"
 )
 
+// keySegment 黑名单的key为 <prefix>:blocklist:<target>
+// prefix可能和其他业务共用，全量同步时只扫描这个前缀，不会扫描到其他业务的key
+const keySegment = "blocklist"
+
 type BlockItem struct {
 	Target string
 	TTL    time.Duration
@@ -18,6 +22,7 @@ type BlockItem struct {
 type BlockList struct {
 	prefix string
 	rdb    *redis.Redis
+	local  *local
 }
 
 func NewBlockList(prefix string, rdb *redis.Redis) *BlockList {
@@ -29,7 +34,13 @@ func NewBlockList(prefix string, rdb *redis.Redis) *BlockList {
 
 func (b *BlockList) Add(ctx context.Context, target string, ttl time.Duration) error {
 	key := b.getKey(target)
-	return b.rdb.Set(ctx, key, "1", ttl).Err()
+	if err := b.rdb.Set(ctx, key, "1", ttl).Err(); err != nil {
+		return err
+	}
+	return b.publish(ctx, &syncMessage{
+		Op:    opAdd,
+		Items: []*syncItem{{Target: target, TTL: ttl.Milliseconds()}},
+	})
 }
 
 func (b *BlockList) BatchAdd(ctx context.Context, items []*BlockItem) error {
@@ -41,11 +52,20 @@ func (b *BlockList) BatchAdd(ctx context.Context, items []*BlockItem) error {
 		key := b.getKey(item.Target)
 		pipe.Set(ctx, key, "1", item.TTL)
 	}
-	_, err := pipe.Exec(ctx)
-	return err
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+	msg := &syncMessage{Op: opAdd, Items: make([]*syncItem, 0, len(items))}
+	for _, item := range items {
+		msg.Items = append(msg.Items, &syncItem{Target: item.Target, TTL: item.TTL.Milliseconds()})
+	}
+	return b.publish(ctx, msg)
 }
 
 func (b *BlockList) Exists(ctx context.Context, target string) (bool, error) {
+	if exists, ok := b.localExists(target); ok {
+		return exists, nil
+	}
 	key := b.getKey(target)
 	n, err := b.rdb.Exists(ctx, key).Result()
 	if err != nil {
@@ -58,6 +78,21 @@ func (b *BlockList) BatchExists(ctx context.Context, targets []string) ([]bool,
 	if len(targets) == 0 {
 		return nil, nil
 	}
+	if b.local != nil && b.local.ready.Load() {
+		result := make([]bool, len(targets))
+		for i, target := range targets {
+			if exists, ok := b.localExists(target); ok {
+				result[i] = exists
+				continue
+			}
+			// 查询过程中本地变为不可用，整体退回到redis
+			result = nil
+			break
+		}
+		if result != nil {
+			return result, nil
+		}
+	}
 	count := len(targets)
 	keys := make([]string, count)
 	for idx, target := range targets {
@@ -86,7 +121,10 @@ func (b *BlockList) BatchExists(ctx context.Context, targets []string) ([]bool,
 
 func (b *BlockList) Remove(ctx context.Context, target string) error {
 	key := b.getKey(target)
-	return b.rdb.Del(ctx, key).Err()
+	if err := b.rdb.Del(ctx, key).Err(); err != nil {
+		return err
+	}
+	return b.publish(ctx, &syncMessage{Op: opRemove, Items: []*syncItem{{Target: target}}})
 }
 
 func (b *BlockList) BatchRemove(ctx context.Context, targets []string) error {
@@ -97,7 +135,14 @@ func (b *BlockList) BatchRemove(ctx context.Context, targets []string) error {
 	for idx, target := range targets {
 		keys[idx] = b.getKey(target)
 	}
-	return b.rdb.Del(ctx, keys...).Err()
+	if err := b.rdb.Del(ctx, keys...).Err(); err != nil {
+		return err
+	}
+	msg := &syncMessage{Op: opRemove, Items: make([]*syncItem, 0, len(targets))}
+	for _, target := range targets {
+		msg.Items = append(msg.Items, &syncItem{Target: target})
+	}
+	return b.publish(ctx, msg)
 }
 
 func (b *BlockList) TTL(ctx context.Context, target string) (time.Duration, error) {
@@ -135,5 +180,5 @@ func (b *BlockList) BatchTTL(ctx context.Context, targets []string) ([]time.Dura
 }
 
 func (b *BlockList) getKey(target string) string {
-	return fmt.Sprintf("%s:%s", b.prefix, target)
+	return fmt.Sprintf("%s:%s:%s", b.prefix, keySegment, target)
 }
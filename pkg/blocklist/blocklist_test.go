@@ -0,0 +1,205 @@
+package blocklist
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	redisv9 "github.com/redis/go-redis/v9"
+
+	"github.com/byteflowing/base/pkg/redis"
+)
+
+const testPrefix = "test"
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Redis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redisv9.NewClient(&redisv9.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return mr, redis.NewFromClient(client)
+}
+
+func newTestLocal(t *testing.T, rdb *redis.Redis, opts *LocalOption) *BlockList {
+	t.Helper()
+	b := NewLocalBlockList(testPrefix, rdb, opts)
+	t.Cleanup(b.Close)
+	waitFor(t, "local blocklist ready", b.local.ready.Load)
+	return b
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBlockList(t *testing.T) {
+	ctx := context.Background()
+	mr, rdb := newTestRedis(t)
+	b := NewBlockList(testPrefix, rdb)
+	if err := b.Add(ctx, "a", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if !mr.Exists("test:blocklist:a") {
+		t.Fatalf("expected key under blocklist prefix, got %v", mr.Keys())
+	}
+	if err := b.BatchAdd(ctx, []*BlockItem{{Target: "b", TTL: time.Minute}, {Target: "c", TTL: time.Minute}}); err != nil {
+		t.Fatal(err)
+	}
+	exists, err := b.BatchExists(ctx, []string{"a", "b", "c", "d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []bool{true, true, true, false}; !equalBools(exists, want) {
+		t.Fatalf("BatchExists = %v, want %v", exists, want)
+	}
+	ttl, err := b.TTL(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ttl <= 0 || ttl > time.Minute {
+		t.Fatalf("unexpected ttl: %v", ttl)
+	}
+	if err := b.Remove(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.BatchRemove(ctx, []string{"b"}); err != nil {
+		t.Fatal(err)
+	}
+	exists, err = b.BatchExists(ctx, []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []bool{false, false, true}; !equalBools(exists, want) {
+		t.Fatalf("BatchExists = %v, want %v", exists, want)
+	}
+	mr.FastForward(time.Minute)
+	if ok, err := b.Exists(ctx, "c"); err != nil || ok {
+		t.Fatalf("expected expired entry, got %v %v", ok, err)
+	}
+}
+
+// TestLocalResyncIgnoresOtherKeys 共用前缀下其他业务的key不能被当作黑名单条目
+func TestLocalResyncIgnoresOtherKeys(t *testing.T) {
+	ctx := context.Background()
+	mr, rdb := newTestRedis(t)
+	if err := NewBlockList(testPrefix, rdb).Add(ctx, "existing", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	mr.Set("test:mfa_tries:jti", "1")
+	mr.Set("test:hw_app_token:client", "token")
+	b := newTestLocal(t, rdb, &LocalOption{})
+	b.local.mu.RLock()
+	entries := len(b.local.entries)
+	_, found := b.local.entries["existing"]
+	b.local.mu.RUnlock()
+	if entries != 1 || !found {
+		t.Fatalf("unexpected local entries: %d, found existing: %v", entries, found)
+	}
+	if ok, err := b.Exists(ctx, "mfa_tries:jti"); err != nil || ok {
+		t.Fatalf("other keys must not be blocked, got %v %v", ok, err)
+	}
+}
+
+func TestLocalSync(t *testing.T) {
+	ctx := context.Background()
+	_, rdb := newTestRedis(t)
+	var added atomic.Value
+	a := newTestLocal(t, rdb, &LocalOption{})
+	b := newTestLocal(t, rdb, &LocalOption{OnAdd: func(targets []string) { added.Store(targets) }})
+	if err := a.Add(ctx, "jti", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "add synced", func() bool { return added.Load() != nil })
+	if targets := added.Load().([]string); len(targets) != 1 || targets[0] != "jti" {
+		t.Fatalf("OnAdd got %v, want [jti]", targets)
+	}
+	if ok, _ := b.localExists("jti"); !ok {
+		t.Fatal("expected jti in local entries")
+	}
+	if err := a.Remove(ctx, "jti"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "remove synced", func() bool {
+		ok, _ := b.localExists("jti")
+		return !ok
+	})
+}
+
+// TestLocalMaxEntries 超过上限后本地不可用，查询退回到redis
+func TestLocalMaxEntries(t *testing.T) {
+	ctx := context.Background()
+	_, rdb := newTestRedis(t)
+	b := newTestLocal(t, rdb, &LocalOption{MaxEntries: 1})
+	if err := b.BatchAdd(ctx, []*BlockItem{{Target: "a", TTL: time.Minute}, {Target: "b", TTL: time.Minute}}); err != nil {
+		t.Fatal(err)
+	}
+	if b.local.ready.Load() {
+		t.Fatal("local should not be ready after exceeding max entries")
+	}
+	exists, err := b.BatchExists(ctx, []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []bool{true, true, false}; !equalBools(exists, want) {
+		t.Fatalf("BatchExists = %v, want %v", exists, want)
+	}
+}
+
+func equalBools(a, b []bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestMigrateLegacyKeys 升级前 <prefix>:<target> 格式的条目迁移后仍然有效，其他key不受影响
+func TestMigrateLegacyKeys(t *testing.T) {
+	ctx := context.Background()
+	mr, rdb := newTestRedis(t)
+	mr.Set("test:old", "1")
+	mr.SetTTL("test:old", time.Minute)
+	mr.Set("test:persist", "1")
+	mr.Set("test:other", "value")
+	mr.SetTTL("test:other", time.Minute)
+	mr.HSet("test:hash", "f", "1")
+	mr.Set("test:mfa_tries:jti", "1")
+	mr.SetTTL("test:mfa_tries:jti", time.Minute)
+	b := newTestLocal(t, rdb, &LocalOption{})
+	n, err := b.MigrateLegacyKeys(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("migrated %d entries, want 1", n)
+	}
+	if ttl := mr.TTL("test:blocklist:old"); ttl <= 0 || ttl > time.Minute {
+		t.Fatalf("unexpected ttl of migrated key: %v", ttl)
+	}
+	if !mr.Exists("test:old") {
+		t.Fatal("legacy key should be kept")
+	}
+	exists, err := b.BatchExists(ctx, []string{"old", "persist", "other", "hash", "mfa_tries:jti"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []bool{true, false, false, false, false}; !equalBools(exists, want) {
+		t.Fatalf("BatchExists = %v, want %v", exists, want)
+	}
+	if n, err := b.MigrateLegacyKeys(ctx); err != nil || n != 1 {
+		t.Fatalf("migrate again = %d, %v", n, err)
+	}
+}
@@ -0,0 +1,90 @@
+package blocklist
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/byteflowing/base/pkg/redis"
+	redisv9 "github.com/redis/go-redis/v9"
+)
+
+// legacyValue 旧版本黑名单条目的值
+const legacyValue = "1"
+
+// MigrateLegacyKeys 把旧版本 <prefix>:<target> 格式的条目迁移到 <prefix>:blocklist:<target>，返回迁移的条目数
+// 旧条目只有一段target并且值为"1"，共用前缀的其他key都带有自己的分段，不会被当作黑名单条目
+// 迁移后保留旧key，滚动升级期间旧版本实例仍然可以读到，过期后自动清理；重复执行不影响结果
+func (b *BlockList) MigrateLegacyKeys(ctx context.Context) (int, error) {
+	var items []*BlockItem
+	err := b.scan(ctx, func(client *redisv9.Client) error {
+		found, err := b.scanLegacyNode(ctx, client)
+		items = append(items, found...)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	for start := 0; start < len(items); start += defaultScanBatchCount {
+		end := min(start+defaultScanBatchCount, len(items))
+		if err := b.BatchAdd(ctx, items[start:end]); err != nil {
+			return 0, err
+		}
+	}
+	return len(items), nil
+}
+
+func (b *BlockList) scanLegacyNode(ctx context.Context, client *redisv9.Client) ([]*BlockItem, error) {
+	prefix := b.prefix + ":"
+	// 旧条目都是字符串，只扫描字符串类型的key
+	iter := client.ScanType(ctx, 0, prefix+"*", defaultScanBatchCount, "string").Iterator()
+	var items []*BlockItem
+	var keys []string
+	flush := func() error {
+		if len(keys) == 0 {
+			return nil
+		}
+		values := make([]*redisv9.StringCmd, len(keys))
+		ttls := make([]*redisv9.DurationCmd, len(keys))
+		_, err := client.Pipelined(ctx, func(pipe redisv9.Pipeliner) error {
+			for i, key := range keys {
+				values[i] = pipe.Get(ctx, key)
+				ttls[i] = pipe.PTTL(ctx, key)
+			}
+			return nil
+		})
+		// 扫描期间过期的key返回redis.Nil，值为空不会被迁移
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		for i, key := range keys {
+			if values[i].Val() != legacyValue {
+				continue
+			}
+			if ttl := ttls[i].Val(); ttl > 0 {
+				items = append(items, &BlockItem{Target: key[len(prefix):], TTL: ttl})
+			}
+		}
+		keys = keys[:0]
+		return nil
+	}
+	for iter.Next(ctx) {
+		key := iter.Val()
+		if strings.Contains(key[len(prefix):], ":") {
+			continue
+		}
+		keys = append(keys, key)
+		if len(keys) >= defaultScanBatchCount {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
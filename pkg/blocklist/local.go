@@ -0,0 +1,316 @@
+package blocklist
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/byteflowing/base/pkg/jsonx"
+	"github.com/byteflowing/base/pkg/redis"
+	redisv9 "github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultChannelSuffix  = "blocklist:sync"
+	defaultMaxEntries     = 1000000
+	defaultSweepInterval  = time.Minute
+	defaultRetryInterval  = time.Second
+	defaultScanBatchCount = 1000
+
+	opAdd    = "add"
+	opRemove = "remove"
+)
+
+type LocalOption struct {
+	// Channel 同步使用的pub/sub频道，默认为 <prefix>:blocklist:sync
+	Channel string
+	// MaxEntries 本地最多保存的条目数，超过后退回到直接查询redis，直到过期清理后恢复
+	MaxEntries int
+	// ResyncInterval 定期从redis全量同步的间隔，<=0表示只在订阅建立时同步
+	ResyncInterval time.Duration
+	// OnAdd 本实例或其他实例新增条目后调用，全量同步发现的新条目也会回调，用于清理依赖黑名单的缓存
+	OnAdd func(targets []string)
+}
+
+// syncMessage 不同实例之间同步黑名单变更的消息
+type syncMessage struct {
+	Op    string      `json:"op"`
+	Items []*syncItem `json:"items"`
+}
+
+type syncItem struct {
+	Target string `json:"target"`
+	TTL    int64  `json:"ttl,omitempty"` // 毫秒
+}
+
+// local 本地完整保存一份黑名单，本地不存在即可判定不在黑名单中
+// 订阅建立后先从redis全量加载，之后通过pub/sub增量同步，订阅断开或条目过多时不可用，查询退回到redis
+type local struct {
+	mu      sync.RWMutex
+	entries map[string]time.Time
+	ready   atomic.Bool
+	// subscribed 订阅正常时才能通过全量同步恢复可用
+	subscribed atomic.Bool
+
+	channel        string
+	maxEntries     int
+	resyncInterval time.Duration
+	onAdd          func(targets []string)
+	done           chan struct{}
+}
+
+// NewLocalBlockList 在redis前增加一层本地缓存的黑名单
+// Add/BatchAdd/Remove/BatchRemove会通过pub/sub通知所有实例，吊销在消息送达后即在所有实例生效
+func NewLocalBlockList(prefix string, rdb *redis.Redis, opts *LocalOption) *BlockList {
+	l := &local{
+		entries:        make(map[string]time.Time),
+		channel:        opts.Channel,
+		maxEntries:     opts.MaxEntries,
+		resyncInterval: opts.ResyncInterval,
+		onAdd:          opts.OnAdd,
+		done:           make(chan struct{}),
+	}
+	if l.channel == "" {
+		l.channel = fmt.Sprintf("%s:%s", prefix, defaultChannelSuffix)
+	}
+	if l.maxEntries <= 0 {
+		l.maxEntries = defaultMaxEntries
+	}
+	b := &BlockList{
+		prefix: prefix,
+		rdb:    rdb,
+		local:  l,
+	}
+	go b.subscribe()
+	go b.maintain()
+	return b
+}
+
+// Close 停止本地同步，之后的查询都直接访问redis
+func (b *BlockList) Close() {
+	if b.local == nil {
+		return
+	}
+	b.local.ready.Store(false)
+	close(b.local.done)
+}
+
+// localExists 本地可用时返回查询结果，ok为false表示需要查询redis
+func (b *BlockList) localExists(target string) (exists, ok bool) {
+	if b.local == nil || !b.local.ready.Load() {
+		return false, false
+	}
+	b.local.mu.RLock()
+	expireAt, found := b.local.entries[target]
+	b.local.mu.RUnlock()
+	return found && time.Now().Before(expireAt), true
+}
+
+// publish 更新本地后通知其他实例，本实例收到自己的消息时重复应用不影响结果
+func (b *BlockList) publish(ctx context.Context, msg *syncMessage) error {
+	if b.local == nil {
+		return nil
+	}
+	b.local.apply(msg)
+	payload, err := jsonx.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return b.rdb.Publish(ctx, b.local.channel, payload).Err()
+}
+
+func (b *BlockList) subscribe() {
+	ctx := context.Background()
+	pubsub := b.rdb.GetUniversalClient().Subscribe(ctx, b.local.channel)
+	defer func() { _ = pubsub.Close() }()
+	go func() {
+		<-b.local.done
+		_ = pubsub.Close()
+	}()
+	for {
+		msg, err := pubsub.Receive(ctx)
+		select {
+		case <-b.local.done:
+			return
+		default:
+		}
+		if err != nil {
+			// 连接断开期间可能丢失消息，下次订阅成功时重新全量同步
+			b.local.subscribed.Store(false)
+			b.local.ready.Store(false)
+			time.Sleep(defaultRetryInterval)
+			continue
+		}
+		switch m := msg.(type) {
+		case *redisv9.Subscription:
+			if m.Kind == "subscribe" {
+				b.local.subscribed.Store(true)
+				b.resync(ctx)
+			}
+		case *redisv9.Message:
+			var sm syncMessage
+			if err := jsonx.UnmarshalFromString(m.Payload, &sm); err != nil {
+				continue
+			}
+			b.local.apply(&sm)
+		}
+	}
+}
+
+// maintain 定期清理过期条目，并按配置定期全量同步
+func (b *BlockList) maintain() {
+	sweep := time.NewTicker(defaultSweepInterval)
+	defer sweep.Stop()
+	var resync <-chan time.Time
+	if b.local.resyncInterval > 0 {
+		ticker := time.NewTicker(b.local.resyncInterval)
+		defer ticker.Stop()
+		resync = ticker.C
+	}
+	for {
+		select {
+		case <-b.local.done:
+			return
+		case <-sweep.C:
+			b.local.sweep()
+			// 全量同步失败或条目过多时在这里重试
+			if !b.local.ready.Load() && b.local.subscribed.Load() {
+				b.resync(context.Background())
+			}
+		case <-resync:
+			b.resync(context.Background())
+		}
+	}
+}
+
+// resync 从redis全量加载黑名单，加载失败时本地保持不可用
+func (b *BlockList) resync(ctx context.Context) {
+	entries := make(map[string]time.Time)
+	err := b.scan(ctx, func(client *redisv9.Client) error {
+		return b.scanNode(ctx, client, entries)
+	})
+	if err != nil {
+		b.local.ready.Store(false)
+		return
+	}
+	b.local.merge(entries)
+}
+
+func (b *BlockList) scan(ctx context.Context, fn func(client *redisv9.Client) error) error {
+	if cluster := b.rdb.GetCluster(); cluster != nil {
+		// ForEachMaster并发调用，fn中会写入共享的结果，逐个节点执行
+		var mu sync.Mutex
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, client *redisv9.Client) error {
+			mu.Lock()
+			defer mu.Unlock()
+			return fn(client)
+		})
+	}
+	return fn(b.rdb.GetClient())
+}
+
+func (b *BlockList) scanNode(ctx context.Context, client *redisv9.Client, entries map[string]time.Time) error {
+	prefixLen := len(b.getKey(""))
+	iter := client.Scan(ctx, 0, b.getKey("*"), defaultScanBatchCount).Iterator()
+	var keys []string
+	flush := func() error {
+		if len(keys) == 0 {
+			return nil
+		}
+		cmds := make([]*redisv9.DurationCmd, len(keys))
+		_, err := client.Pipelined(ctx, func(pipe redisv9.Pipeliner) error {
+			for i, key := range keys {
+				cmds[i] = pipe.PTTL(ctx, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for i, cmd := range cmds {
+			// 黑名单的key都带有过期时间，没有过期时间或已删除的key不是黑名单条目
+			if ttl := cmd.Val(); ttl > 0 {
+				entries[keys[i][prefixLen:]] = now.Add(ttl)
+			}
+		}
+		keys = keys[:0]
+		return nil
+	}
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) >= defaultScanBatchCount {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
+
+func (l *local) apply(msg *syncMessage) {
+	var added []string
+	l.mu.Lock()
+	now := time.Now()
+	for _, item := range msg.Items {
+		switch msg.Op {
+		case opAdd:
+			l.entries[item.Target] = now.Add(time.Duration(item.TTL) * time.Millisecond)
+			added = append(added, item.Target)
+		case opRemove:
+			delete(l.entries, item.Target)
+		}
+	}
+	l.checkSize()
+	l.mu.Unlock()
+	l.notifyAdd(added)
+}
+
+// merge 合并全量同步的结果，同步期间通过消息新增的条目不会被覆盖
+// 同步期间被移除的条目可能重新加入，只会多拒绝不会漏判，过期后自动清理
+func (l *local) merge(entries map[string]time.Time) {
+	var added []string
+	l.mu.Lock()
+	for target, expireAt := range entries {
+		old, ok := l.entries[target]
+		if !ok {
+			added = append(added, target)
+		}
+		if !ok || expireAt.After(old) {
+			l.entries[target] = expireAt
+		}
+	}
+	l.ready.Store(l.subscribed.Load() && len(l.entries) <= l.maxEntries)
+	l.mu.Unlock()
+	l.notifyAdd(added)
+}
+
+// notifyAdd 在锁外回调，回调中可以再查询黑名单
+func (l *local) notifyAdd(targets []string) {
+	if l.onAdd != nil && len(targets) > 0 {
+		l.onAdd(targets)
+	}
+}
+
+func (l *local) sweep() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	for target, expireAt := range l.entries {
+		if !now.Before(expireAt) {
+			delete(l.entries, target)
+		}
+	}
+}
+
+// checkSize 超过上限时本地不再完整，不能再用于判定不存在
+func (l *local) checkSize() {
+	if len(l.entries) > l.maxEntries {
+		l.ready.Store(false)
+	}
+}
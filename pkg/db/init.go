@@ -56,7 +56,9 @@ func getGormConfig(c *configv1.DbConfig) *gorm.Config {
 	config := &gorm.Config{
 		SkipDefaultTransaction: true,
 		PrepareStmt:            true,
-		Logger:                 logger.Default,
+		// 把驱动的错误转换为gorm的错误，e.g. 唯一索引冲突返回gorm.ErrDuplicatedKey
+		TranslateError: true,
+		Logger:         logger.Default,
 	}
 	if c.Log == nil {
 		config.Logger = logger.Default.LogMode(logger.Silent)
@@ -46,26 +46,28 @@ func New(issuer, secret string) *Jwt {
 //	nbf: 生效时间
 //	jti: token对应的uuid可作为sessionID
 //	token_type: 生成token的类型，业务自定义
-//	其他：extra中传递自定义字段
+//	其他：extra中传递自定义字段，不能覆盖以上字段
 func (j *Jwt) Generate(subject, tokenType string, ttl time.Duration, extra map[string]any) (*Token, error) {
 	now := jwt.NewNumericDate(time.Now())
 	jti, err := idx.UUIDv7()
 	if err != nil {
 		return nil, err
 	}
-	claims := jwt.MapClaims{
-		"iss":        j.issuer,
-		"sub":        subject,
-		"iat":        now.Unix(),
-		"nbf":        now.Unix(),
-		"exp":        now.Add(ttl).Unix(),
-		"jti":        jti,
-		TokenTypeKey: tokenType,
-	}
+	claims := make(jwt.MapClaims, len(extra)+7)
 	for k, v := range extra {
 		claims[k] = v
 	}
-	signed, err := jwt.NewWithClaims(j.signMethod, claims).SignedString(j.secretKey)
+	claims["iss"] = j.issuer
+	claims["sub"] = subject
+	claims["iat"] = now.Unix()
+	claims["nbf"] = now.Unix()
+	claims["exp"] = now.Add(ttl).Unix()
+	claims["jti"] = jti
+	claims[TokenTypeKey] = tokenType
+	signed, err := jwt.NewWithClaims(j.signMethod, claims).SignedString([]byte(j.secretKey))
+	if err != nil {
+		return nil, err
+	}
 	return &Token{
 		Token:  signed,
 		Jti:    jti,
@@ -83,14 +85,14 @@ func (j *Jwt) Generate(subject, tokenType string, ttl time.Duration, extra map[s
 //	iat: 签发时间
 //	nbf: 生效时间
 //	jti: token对应的uuid可作为sessionID
-//	其他：签发token时传递extra中的字段
+//	其他：签发token时传递extra中的字段，数字类型解析为json.Number
 func (j *Jwt) Parse(tokenString, tokenType string) (jwt.MapClaims, error) {
 	t, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
 		if token.Method.Alg() != j.signMethod.Alg() {
 			return nil, ecode.ErrJwtSignMethodMismatch
 		}
 		return []byte(j.secretKey), nil
-	})
+	}, jwt.WithJSONNumber())
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,72 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/byteflowing/base/ecode"
+)
+
+func TestGenerateParse(t *testing.T) {
+	j := New("test-issuer", "test-secret")
+	token, err := j.Generate("10001", "access", time.Minute, map[string]any{
+		"tenant_id": "t1",
+		"level":     3,
+		// 不能覆盖标准字段
+		"sub": "20002",
+		"exp": time.Now().Add(time.Hour).Unix(),
+		"jti": "forged",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Token == "" {
+		t.Fatal("token is empty")
+	}
+	claims, err := j.Parse(token.Token, "access")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub, _ := claims.GetSubject(); sub != "10001" {
+		t.Fatalf("unexpected sub: %s", sub)
+	}
+	if claims["jti"] != token.Jti {
+		t.Fatalf("unexpected jti: %v", claims["jti"])
+	}
+	exp, err := claims.GetExpirationTime()
+	if err != nil || exp.Unix() != token.Exp.Unix() {
+		t.Fatalf("unexpected exp: %v, %v", exp, err)
+	}
+	if claims["tenant_id"] != "t1" {
+		t.Fatalf("unexpected tenant_id: %v", claims["tenant_id"])
+	}
+	if level, ok := claims["level"].(json.Number); !ok || level.String() != "3" {
+		t.Fatalf("unexpected level: %#v", claims["level"])
+	}
+}
+
+func TestParseMismatch(t *testing.T) {
+	j := New("test-issuer", "test-secret")
+	token, err := j.Generate("10001", "access", time.Minute, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := j.Parse(token.Token, "refresh"); !errors.Is(err, ecode.ErrJwtTokenTypeMismatch) {
+		t.Fatalf("expected ErrJwtTokenTypeMismatch, got %v", err)
+	}
+	if _, err := New("other-issuer", "test-secret").Parse(token.Token, "access"); !errors.Is(err, ecode.ErrJwtIssuerMismatch) {
+		t.Fatalf("expected ErrJwtIssuerMismatch, got %v", err)
+	}
+	if _, err := New("test-issuer", "other-secret").Parse(token.Token, "access"); err == nil {
+		t.Fatal("expected signature error")
+	}
+	expired, err := j.Generate("10001", "access", -time.Minute, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := j.Parse(expired.Token, "access"); err == nil {
+		t.Fatal("expected expired error")
+	}
+}
@@ -0,0 +1,241 @@
+// Package ldap
+// 通过LDAP bind校验用户名和密码，适用于OpenLDAP和Active Directory
+// 支持两种方式：使用DN模板直接bind，或者先用服务账号搜索用户DN再bind
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+const defaultTimeout = 10 * time.Second
+
+var (
+	ErrInvalidCredentials = errors.New("ldap: invalid credentials")
+	ErrInvalidConfig      = errors.New("ldap: invalid config")
+	ErrInvalidCACert      = errors.New("ldap: invalid ca cert")
+)
+
+type Config struct {
+	URL                string        // ldap://host:389 或 ldaps://host:636
+	StartTLS           bool          // ldap://连接后升级为TLS
+	InsecureSkipVerify bool          // 不校验服务端证书，只用于测试环境
+	CACert             string        // PEM格式的CA证书，为空时使用系统证书
+	Timeout            time.Duration // 连接和请求超时，为空时默认10秒
+	// BindDNTemplate 直接bind时使用的DN模板，%s替换为用户名
+	// e.g. uid=%s,ou=people,dc=example,dc=com，AD可以使用UPN：%s@corp.example.com
+	BindDNTemplate string
+	// 搜索用户时使用的服务账号，为空时匿名搜索
+	BindDN       string
+	BindPassword string
+	// BaseDN和UserFilter用于搜索用户，%s替换为转义后的用户名
+	// e.g. (&(objectClass=person)(uid=%s))，AD：(&(objectClass=user)(sAMAccountName=%s))
+	// 使用DN模板时，配置后用于bind成功后读取用户属性，否则直接读取DN对应的条目
+	BaseDN     string
+	UserFilter string
+	Attributes []string // 需要读取的用户属性
+}
+
+// Entry bind成功的用户条目
+type Entry struct {
+	DN         string
+	Attributes map[string][]string
+	raw        map[string][][]byte
+}
+
+// Get 获取属性的第一个值，属性名不区分大小写
+func (e *Entry) Get(name string) string {
+	for k, v := range e.Attributes {
+		if strings.EqualFold(k, name) && len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+// GetHex 获取属性第一个值原始字节的hex编码，用作用户标识时不受编码影响
+// e.g. AD的objectGUID部分字节组合可能恰好是合法的UTF-8，Get会按字符串返回
+func (e *Entry) GetHex(name string) string {
+	for k, v := range e.raw {
+		if strings.EqualFold(k, name) && len(v) > 0 {
+			return hex.EncodeToString(v[0])
+		}
+	}
+	return ""
+}
+
+type Client struct {
+	cfg       *Config
+	tlsConfig *tls.Config
+}
+
+func New(cfg *Config) (*Client, error) {
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{cfg: cfg, tlsConfig: tlsConfig}, nil
+}
+
+// Validate 只校验配置，不创建客户端
+func Validate(cfg *Config) error {
+	_, err := newTLSConfig(cfg)
+	return err
+}
+
+func newTLSConfig(cfg *Config) (*tls.Config, error) {
+	if cfg.URL == "" {
+		return nil, ErrInvalidConfig
+	}
+	if cfg.BindDNTemplate == "" && (cfg.BaseDN == "" || cfg.UserFilter == "") {
+		return nil, ErrInvalidConfig
+	}
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+	if cfg.CACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(cfg.CACert)) {
+			return nil, ErrInvalidCACert
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// Authenticate 校验用户名和密码，成功后返回用户条目
+// 密码为空时LDAP会当作匿名bind并返回成功，这里直接拒绝
+func (c *Client) Authenticate(ctx context.Context, username, password string) (*Entry, error) {
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if c.cfg.BindDNTemplate != "" {
+		return c.bindWithTemplate(conn, username, password)
+	}
+	return c.searchAndBind(conn, username, password)
+}
+
+func (c *Client) bindWithTemplate(conn *ldap.Conn, username, password string) (*Entry, error) {
+	dn := fmt.Sprintf(c.cfg.BindDNTemplate, ldap.EscapeDN(username))
+	if err := bind(conn, dn, password); err != nil {
+		return nil, err
+	}
+	if c.cfg.BaseDN != "" && c.cfg.UserFilter != "" {
+		return c.searchUser(conn, username)
+	}
+	result, err := conn.Search(ldap.NewSearchRequest(
+		dn, ldap.ScopeBaseObject, ldap.NeverDerefAliases, 1, 0, false,
+		"(objectClass=*)", c.cfg.Attributes, nil,
+	))
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Entries) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+	return toEntry(result.Entries[0]), nil
+}
+
+func (c *Client) searchAndBind(conn *ldap.Conn, username, password string) (*Entry, error) {
+	if c.cfg.BindDN != "" {
+		if err := conn.Bind(c.cfg.BindDN, c.cfg.BindPassword); err != nil {
+			return nil, fmt.Errorf("ldap: service account bind failed: %w", err)
+		}
+	}
+	entry, err := c.searchUser(conn, username)
+	if err != nil {
+		return nil, err
+	}
+	if err := bind(conn, entry.DN, password); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// searchUser 用户不存在或者匹配到多个条目时都返回ErrInvalidCredentials，不暴露用户是否存在
+func (c *Client) searchUser(conn *ldap.Conn, username string) (*Entry, error) {
+	result, err := conn.Search(ldap.NewSearchRequest(
+		c.cfg.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, 0, false,
+		fmt.Sprintf(c.cfg.UserFilter, ldap.EscapeFilter(username)), c.cfg.Attributes, nil,
+	))
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return nil, err
+	}
+	if result == nil || len(result.Entries) != 1 {
+		return nil, ErrInvalidCredentials
+	}
+	return toEntry(result.Entries[0]), nil
+}
+
+func (c *Client) dial(ctx context.Context) (*ldap.Conn, error) {
+	timeout := c.cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if remain := time.Until(deadline); remain < timeout {
+			timeout = remain
+		}
+	}
+	conn, err := ldap.DialURL(
+		c.cfg.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: timeout}),
+		ldap.DialWithTLSConfig(c.tlsConfig),
+	)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(timeout)
+	if c.cfg.StartTLS {
+		if err := conn.StartTLS(c.tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+func bind(conn *ldap.Conn, dn, password string) error {
+	if err := conn.Bind(dn, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return ErrInvalidCredentials
+		}
+		return err
+	}
+	return nil
+}
+
+// toEntry 二进制属性（e.g. AD的objectGUID）转为hex字符串
+func toEntry(e *ldap.Entry) *Entry {
+	entry := &Entry{
+		DN:         e.DN,
+		Attributes: make(map[string][]string, len(e.Attributes)),
+		raw:        make(map[string][][]byte, len(e.Attributes)),
+	}
+	for _, attr := range e.Attributes {
+		entry.raw[attr.Name] = attr.ByteValues
+		values := make([]string, 0, len(attr.ByteValues))
+		for _, v := range attr.ByteValues {
+			if utf8.Valid(v) {
+				values = append(values, string(v))
+			} else {
+				values = append(values, hex.EncodeToString(v))
+			}
+		}
+		entry.Attributes[attr.Name] = values
+	}
+	return entry
+}
@@ -0,0 +1,251 @@
+package ldap
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+type testEntry struct {
+	dn       string
+	password string
+	attrs    map[string][]string
+}
+
+// testServer 最小的LDAPv3服务端，只支持simple bind、search（and/equality/present）和unbind
+type testServer struct {
+	listener net.Listener
+	entries  []testEntry
+}
+
+func newTestServer(t *testing.T, entries []testEntry) *testServer {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &testServer{listener: l, entries: entries}
+	go s.serve()
+	t.Cleanup(func() { _ = l.Close() })
+	return s
+}
+
+func (s *testServer) url() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+func (s *testServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *testServer) handle(conn net.Conn) {
+	defer conn.Close()
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		id := packet.Children[0].Value.(int64)
+		op := packet.Children[1]
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			dn := op.Children[1].Value.(string)
+			password := op.Children[2].Data.String()
+			code := uint16(ldap.LDAPResultInvalidCredentials)
+			for _, e := range s.entries {
+				if strings.EqualFold(e.dn, dn) && e.password == password {
+					code = ldap.LDAPResultSuccess
+				}
+			}
+			s.write(conn, id, ldap.ApplicationBindResponse, code)
+		case ldap.ApplicationSearchRequest:
+			base := op.Children[0].Value.(string)
+			scope := op.Children[1].Value.(int64)
+			for _, e := range s.entries {
+				if !inScope(e.dn, base, scope) || !matchFilter(op.Children[6], e) {
+					continue
+				}
+				s.writeEntry(conn, id, e)
+			}
+			s.write(conn, id, ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess)
+		case ldap.ApplicationUnbindRequest:
+			return
+		}
+	}
+}
+
+func (s *testServer) write(conn net.Conn, id int64, tag ber.Tag, code uint16) {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "MessageID"))
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "resultCode"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matchedDN"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "diagnosticMessage"))
+	packet.AppendChild(result)
+	_, _ = conn.Write(packet.Bytes())
+}
+
+func (s *testServer) writeEntry(conn net.Conn, id int64, e testEntry) {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "MessageID"))
+	entry := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	entry.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.dn, "objectName"))
+	attrs := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attributes")
+	for name, values := range e.attrs {
+		attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attribute")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "vals")
+		for _, v := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "value"))
+		}
+		attr.AppendChild(set)
+		attrs.AppendChild(attr)
+	}
+	entry.AppendChild(attrs)
+	packet.AppendChild(entry)
+	_, _ = conn.Write(packet.Bytes())
+}
+
+func inScope(dn, base string, scope int64) bool {
+	if scope == ldap.ScopeBaseObject {
+		return strings.EqualFold(dn, base)
+	}
+	return strings.HasSuffix(strings.ToLower(dn), strings.ToLower(base))
+}
+
+func matchFilter(f *ber.Packet, e testEntry) bool {
+	switch f.Tag {
+	case ldap.FilterAnd:
+		for _, child := range f.Children {
+			if !matchFilter(child, e) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterEqualityMatch:
+		name, value := f.Children[0].Value.(string), f.Children[1].Value.(string)
+		for k, values := range e.attrs {
+			if !strings.EqualFold(k, name) {
+				continue
+			}
+			for _, v := range values {
+				if strings.EqualFold(v, value) {
+					return true
+				}
+			}
+		}
+		return false
+	case ldap.FilterPresent:
+		name := f.Data.String()
+		if strings.EqualFold(name, "objectClass") {
+			return true
+		}
+		_, ok := e.attrs[name]
+		return ok
+	}
+	return false
+}
+
+var testEntries = []testEntry{
+	{
+		dn:       "uid=alice,ou=people,dc=example,dc=com",
+		password: "alice-pass",
+		attrs: map[string][]string{
+			"uid":         {"alice"},
+			"mail":        {"alice@example.com"},
+			"displayName": {"Alice"},
+			"objectGUID":  {string([]byte{0xff, 0x01, 0x02})},
+		},
+	},
+	{
+		dn:       "cn=svc,dc=example,dc=com",
+		password: "svc-pass",
+		attrs:    map[string][]string{"cn": {"svc"}},
+	},
+}
+
+func TestAuthenticateWithTemplate(t *testing.T) {
+	s := newTestServer(t, testEntries)
+	client, err := New(&Config{
+		URL:            s.url(),
+		BindDNTemplate: "uid=%s,ou=people,dc=example,dc=com",
+		Attributes:     []string{"mail", "displayName", "objectGUID"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := client.Authenticate(context.Background(), "alice", "alice-pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.DN != "uid=alice,ou=people,dc=example,dc=com" {
+		t.Fatalf("unexpected dn: %s", entry.DN)
+	}
+	if got := entry.Get("MAIL"); got != "alice@example.com" {
+		t.Fatalf("unexpected mail: %s", got)
+	}
+	if got := entry.Get("objectGUID"); got != "ff0102" {
+		t.Fatalf("unexpected objectGUID: %s", got)
+	}
+	if got := entry.GetHex("objectguid"); got != "ff0102" {
+		t.Fatalf("unexpected hex objectGUID: %s", got)
+	}
+	if got := entry.GetHex("mail"); got != hex.EncodeToString([]byte("alice@example.com")) {
+		t.Fatalf("unexpected hex mail: %s", got)
+	}
+	if _, err := client.Authenticate(context.Background(), "alice", "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+	}
+	if _, err := client.Authenticate(context.Background(), "alice", ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected ErrInvalidCredentials for empty password, got %v", err)
+	}
+}
+
+func TestAuthenticateWithSearch(t *testing.T) {
+	s := newTestServer(t, testEntries)
+	client, err := New(&Config{
+		URL:          s.url(),
+		BindDN:       "cn=svc,dc=example,dc=com",
+		BindPassword: "svc-pass",
+		BaseDN:       "ou=people,dc=example,dc=com",
+		UserFilter:   "(&(objectClass=*)(uid=%s))",
+		Attributes:   []string{"uid", "mail", "displayName"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := client.Authenticate(context.Background(), "alice", "alice-pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := entry.Get("displayName"); got != "Alice" {
+		t.Fatalf("unexpected displayName: %s", got)
+	}
+	if _, err := client.Authenticate(context.Background(), "bob", "bob-pass"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected ErrInvalidCredentials for unknown user, got %v", err)
+	}
+	if _, err := client.Authenticate(context.Background(), "alice", "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+	}
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	if _, err := New(&Config{URL: "ldap://127.0.0.1"}); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected ErrInvalidConfig, got %v", err)
+	}
+	if _, err := New(&Config{URL: "ldap://127.0.0.1", BindDNTemplate: "%s", CACert: "bad"}); !errors.Is(err, ErrInvalidCACert) {
+		t.Fatalf("expected ErrInvalidCACert, got %v", err)
+	}
+}
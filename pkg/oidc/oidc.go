@@ -0,0 +1,446 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/byteflowing/base/pkg/httpx"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+
+	authMethodBasic = "client_secret_basic"
+	authMethodPost  = "client_secret_post"
+	pkceMethodS256  = "S256"
+	pkceVerifierLen = 32
+
+	defaultJwksTTL = time.Hour
+	// kid未命中时重新拉取jwks的最小间隔，避免伪造的kid导致频繁请求IdP
+	jwksMinRefresh = 10 * time.Second
+)
+
+var (
+	ErrInvalidToken  = errors.New("oidc: invalid id token")
+	ErrNonceMismatch = errors.New("oidc: nonce mismatch")
+	ErrNoIDToken     = errors.New("oidc: no id token in token response")
+	ErrNoUserInfo    = errors.New("oidc: userinfo endpoint not configured")
+)
+
+type Config struct {
+	Issuer       string        // IdP的issuer，用于获取discovery文档以及校验id token的iss
+	ClientID     string        // 应用的client id，同时用于校验id token的aud
+	Audiences    []string      // 除ClientID外允许的aud，e.g. Apple的iOS bundle id和web的services id
+	ClientSecret string        // 应用的client secret，public client可以为空
+	RedirectURL  string        // 授权回调地址，需要和发起授权时一致
+	Scopes       []string      // 为空时使用openid profile email
+	JwksTTL      time.Duration // jwks缓存时间，为空时默认1小时
+	// 不支持discovery的IdP（e.g. GitHub）可以直接配置端点，配置后不再请求discovery文档
+	AuthURL     string
+	TokenURL    string
+	UserInfoURL string
+	JwksURL     string
+	HTTPClient  *http.Client
+}
+
+// Discovery OpenID Provider Metadata中用到的字段
+type Discovery struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JwksURI                           string   `json:"jwks_uri"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+type Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	IDToken      string `json:"id_token"`
+	Scope        string `json:"scope"`
+}
+
+type tokenError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type Client struct {
+	cfg        *Config
+	httpClient *http.Client
+
+	mu        sync.Mutex
+	discovery *Discovery
+
+	jwksMu        sync.RWMutex
+	keys          map[string]any
+	keysFetchedAt time.Time
+}
+
+func New(cfg *Config) *Client {
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = httpx.NewClient(httpx.GetDefaultConfig())
+	}
+	return &Client{
+		cfg:        cfg,
+		httpClient: httpClient,
+	}
+}
+
+// Close 关闭空闲的http连接，正在进行的请求不受影响
+func (c *Client) Close() error {
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// GeneratePKCE 生成PKCE的code_verifier以及S256方式的code_challenge
+// 文档：https://datatracker.ietf.org/doc/html/rfc7636
+func GeneratePKCE() (verifier, challenge string, err error) {
+	b := make([]byte, pkceVerifierLen)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", err
+	}
+	verifier = base64.RawURLEncoding.EncodeToString(b)
+	return verifier, CodeChallenge(verifier), nil
+}
+
+// CodeChallenge 计算S256方式的code_challenge
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// Discover 获取discovery文档，成功后缓存在内存中
+// 文档：https://openid.net/specs/openid-connect-discovery-1_0.html
+func (c *Client) Discover(ctx context.Context) (*Discovery, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.discovery != nil {
+		return c.discovery, nil
+	}
+	if c.cfg.TokenURL != "" {
+		c.discovery = &Discovery{
+			Issuer:                c.cfg.Issuer,
+			AuthorizationEndpoint: c.cfg.AuthURL,
+			TokenEndpoint:         c.cfg.TokenURL,
+			UserInfoEndpoint:      c.cfg.UserInfoURL,
+			JwksURI:               c.cfg.JwksURL,
+		}
+		return c.discovery, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.cfg.Issuer, "/")+discoveryPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	d := &Discovery{}
+	if err := c.doJSON(req, d); err != nil {
+		return nil, err
+	}
+	if d.Issuer != c.cfg.Issuer {
+		return nil, fmt.Errorf("oidc: issuer mismatch, want %s got %s", c.cfg.Issuer, d.Issuer)
+	}
+	c.discovery = d
+	return d, nil
+}
+
+// AuthCodeURL 生成授权地址，codeChallenge为空时不使用PKCE
+func (c *Client) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	d, err := c.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", c.cfg.ClientID)
+	v.Set("redirect_uri", c.cfg.RedirectURL)
+	v.Set("scope", strings.Join(c.scopes(), " "))
+	if state != "" {
+		v.Set("state", state)
+	}
+	if nonce != "" {
+		v.Set("nonce", nonce)
+	}
+	if codeChallenge != "" {
+		v.Set("code_challenge", codeChallenge)
+		v.Set("code_challenge_method", pkceMethodS256)
+	}
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return d.AuthorizationEndpoint + sep + v.Encode(), nil
+}
+
+// Exchange 使用授权码换取token，codeVerifier为发起授权时生成的PKCE code_verifier
+func (c *Client) Exchange(ctx context.Context, code, codeVerifier string) (*Token, error) {
+	d, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	v := url.Values{}
+	v.Set("grant_type", "authorization_code")
+	v.Set("code", code)
+	v.Set("redirect_uri", c.cfg.RedirectURL)
+	if codeVerifier != "" {
+		v.Set("code_verifier", codeVerifier)
+	}
+	useBasic := c.cfg.ClientSecret != "" && !c.preferPost(d)
+	if !useBasic {
+		v.Set("client_id", c.cfg.ClientID)
+		if c.cfg.ClientSecret != "" {
+			v.Set("client_secret", c.cfg.ClientSecret)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if useBasic {
+		req.SetBasicAuth(url.QueryEscape(c.cfg.ClientID), url.QueryEscape(c.cfg.ClientSecret))
+	}
+	token := &Token{}
+	if err := c.doJSON(req, token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("oidc: empty access token")
+	}
+	return token, nil
+}
+
+// VerifyIDToken 使用jwks校验id token的签名、iss、aud、exp，nonce不为空时同时校验nonce
+// 文档：https://openid.net/specs/openid-connect-core-1_0.html#IDTokenValidation
+func (c *Client) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (jwt.MapClaims, error) {
+	d, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	methods := d.IDTokenSigningAlgValuesSupported
+	if len(methods) == 0 {
+		methods = []string{"RS256"}
+	}
+	methods = slices.DeleteFunc(slices.Clone(methods), func(alg string) bool {
+		return alg == "none" || strings.HasPrefix(alg, "HS")
+	})
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithIssuer(d.Issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if len(c.cfg.Audiences) == 0 {
+		opts = append(opts, jwt.WithAudience(c.cfg.ClientID))
+	}
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return c.getKey(ctx, kid)
+	}, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if len(c.cfg.Audiences) > 0 && !c.validAudience(claims) {
+		return nil, fmt.Errorf("%w: invalid audience", ErrInvalidToken)
+	}
+	if nonce != "" {
+		if n, _ := claims["nonce"].(string); n != nonce {
+			return nil, ErrNonceMismatch
+		}
+	}
+	return claims, nil
+}
+
+// UserInfo 使用access token获取用户信息
+func (c *Client) UserInfo(ctx context.Context, accessToken string) (map[string]any, error) {
+	d, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if d.UserInfoEndpoint == "" {
+		return nil, ErrNoUserInfo
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.UserInfoEndpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	info := map[string]any{}
+	if err := c.doJSON(req, &info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// Claims 换取token后返回用户的claims
+// 有id token时校验并使用id token的claims，同时用userinfo补充id token中没有的字段；
+// 没有id token时（纯OAuth2的IdP）只使用userinfo
+func (c *Client) Claims(ctx context.Context, token *Token, nonce string) (map[string]any, error) {
+	d, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims := map[string]any{}
+	if token.IDToken != "" {
+		idClaims, err := c.VerifyIDToken(ctx, token.IDToken, nonce)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range idClaims {
+			claims[k] = v
+		}
+	} else if d.UserInfoEndpoint == "" {
+		return nil, ErrNoIDToken
+	}
+	if d.UserInfoEndpoint == "" {
+		return claims, nil
+	}
+	info, err := c.UserInfo(ctx, token.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	// userinfo的sub必须和id token一致
+	if sub, ok := claims["sub"]; ok && info["sub"] != nil && fmt.Sprint(info["sub"]) != fmt.Sprint(sub) {
+		return nil, fmt.Errorf("%w: userinfo subject mismatch", ErrInvalidToken)
+	}
+	for k, v := range info {
+		if _, ok := claims[k]; !ok {
+			claims[k] = v
+		}
+	}
+	return claims, nil
+}
+
+func (c *Client) validAudience(claims jwt.MapClaims) bool {
+	aud, err := claims.GetAudience()
+	if err != nil {
+		return false
+	}
+	for _, a := range aud {
+		if a == c.cfg.ClientID || slices.Contains(c.cfg.Audiences, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// preferPost 默认使用client_secret_basic，IdP只声明支持client_secret_post时把密钥放在表单中
+func (c *Client) preferPost(d *Discovery) bool {
+	methods := d.TokenEndpointAuthMethodsSupported
+	return slices.Contains(methods, authMethodPost) && !slices.Contains(methods, authMethodBasic)
+}
+
+func (c *Client) scopes() []string {
+	if len(c.cfg.Scopes) > 0 {
+		return c.cfg.Scopes
+	}
+	return []string{"openid", "profile", "email"}
+}
+
+func (c *Client) getKey(ctx context.Context, kid string) (any, error) {
+	ttl := c.cfg.JwksTTL
+	if ttl <= 0 {
+		ttl = defaultJwksTTL
+	}
+	c.jwksMu.RLock()
+	key, ok := c.lookupKey(kid)
+	fresh := time.Since(c.keysFetchedAt) < ttl
+	c.jwksMu.RUnlock()
+	if ok && fresh {
+		return key, nil
+	}
+	c.jwksMu.Lock()
+	defer c.jwksMu.Unlock()
+	if key, ok := c.lookupKey(kid); ok && time.Since(c.keysFetchedAt) < ttl {
+		return key, nil
+	}
+	if time.Since(c.keysFetchedAt) >= jwksMinRefresh {
+		if err := c.fetchKeys(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if key, ok := c.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("oidc: key %q not found", kid)
+}
+
+// lookupKey kid为空并且jwks中只有一个key时使用该key
+func (c *Client) lookupKey(kid string) (any, bool) {
+	if kid == "" && len(c.keys) == 1 {
+		for _, k := range c.keys {
+			return k, true
+		}
+	}
+	k, ok := c.keys[kid]
+	return k, ok
+}
+
+func (c *Client) fetchKeys(ctx context.Context) error {
+	d, err := c.Discover(ctx)
+	if err != nil {
+		return err
+	}
+	if d.JwksURI == "" {
+		return errors.New("oidc: jwks_uri not configured")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.JwksURI, nil)
+	if err != nil {
+		return err
+	}
+	set := &jwkSet{}
+	if err := c.doJSON(req, set); err != nil {
+		return err
+	}
+	keys, err := set.publicKeys()
+	if err != nil {
+		return err
+	}
+	c.keys = keys
+	c.keysFetchedAt = time.Now()
+	return nil
+}
+
+func (c *Client) doJSON(req *http.Request, v any) error {
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		te := &tokenError{}
+		if json.Unmarshal(body, te) == nil && te.Error != "" {
+			return fmt.Errorf("oidc: %s: %s", te.Error, te.ErrorDescription)
+		}
+		return fmt.Errorf("oidc: unexpected status %d from %s", resp.StatusCode, req.URL.Host)
+	}
+	// 部分IdP出错时也返回200
+	te := &tokenError{}
+	if json.Unmarshal(body, te) == nil && te.Error != "" {
+		return fmt.Errorf("oidc: %s: %s", te.Error, te.ErrorDescription)
+	}
+	return json.Unmarshal(body, v)
+}
@@ -0,0 +1,295 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID     = "test-client"
+	testClientSecret = "test-secret"
+	testRedirectURL  = "https://app.example.com/callback"
+	testCode         = "test-code"
+	testSubject      = "user-1"
+	testKid          = "key-1"
+)
+
+// stubProvider 进程内的OIDC IdP，只实现测试需要的discovery、jwks、token和userinfo端点
+type stubProvider struct {
+	server    *httptest.Server
+	key       *rsa.PrivateKey
+	challenge string
+	nonce     string
+	audience  string
+}
+
+func newStubProvider(t *testing.T) *stubProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &stubProvider{key: key, audience: testClientID}
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, p.discovery)
+	mux.HandleFunc("/jwks", p.jwks)
+	mux.HandleFunc("/token", p.token)
+	mux.HandleFunc("/userinfo", p.userinfo)
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *stubProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, &Discovery{
+		Issuer:                            p.server.URL,
+		AuthorizationEndpoint:             p.server.URL + "/authorize",
+		TokenEndpoint:                     p.server.URL + "/token",
+		UserInfoEndpoint:                  p.server.URL + "/userinfo",
+		JwksURI:                           p.server.URL + "/jwks",
+		IDTokenSigningAlgValuesSupported:  []string{"RS256"},
+		TokenEndpointAuthMethodsSupported: []string{authMethodBasic},
+	})
+}
+
+func (p *stubProvider) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := p.key.PublicKey
+	writeJSON(w, http.StatusOK, &jwkSet{Keys: []*jwk{{
+		Kty: "RSA",
+		Kid: testKid,
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}}})
+}
+
+func (p *stubProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, &tokenError{Error: "invalid_request"})
+		return
+	}
+	id, secret, ok := r.BasicAuth()
+	if !ok || id != testClientID || secret != testClientSecret {
+		writeJSON(w, http.StatusUnauthorized, &tokenError{Error: "invalid_client"})
+		return
+	}
+	if r.Form.Get("code") != testCode || r.Form.Get("redirect_uri") != testRedirectURL {
+		writeJSON(w, http.StatusBadRequest, &tokenError{Error: "invalid_grant"})
+		return
+	}
+	if CodeChallenge(r.Form.Get("code_verifier")) != p.challenge {
+		writeJSON(w, http.StatusBadRequest, &tokenError{Error: "invalid_grant", ErrorDescription: "pkce verification failed"})
+		return
+	}
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   p.server.URL,
+		"sub":   testSubject,
+		"aud":   p.audience,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Minute).Unix(),
+		"nonce": p.nonce,
+		"email": "alice@example.com",
+	})
+	idToken.Header["kid"] = testKid
+	signed, err := idToken.SignedString(p.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, &tokenError{Error: "server_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, &Token{
+		AccessToken: "access-token",
+		TokenType:   "Bearer",
+		ExpiresIn:   60,
+		IDToken:     signed,
+	})
+}
+
+func (p *stubProvider) userinfo(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer access-token" {
+		writeJSON(w, http.StatusUnauthorized, &tokenError{Error: "invalid_token"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"sub":     testSubject,
+		"email":   "other@example.com",
+		"name":    "Alice",
+		"picture": "https://example.com/alice.png",
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newTestClient(p *stubProvider) *Client {
+	return New(&Config{
+		Issuer:       p.server.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+		HTTPClient:   p.server.Client(),
+	})
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	p := newStubProvider(t)
+	c := newTestClient(p)
+	ctx := context.Background()
+
+	verifier, challenge, err := GeneratePKCE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.challenge = challenge
+	p.nonce = "nonce-1"
+
+	authURL, err := c.AuthCodeURL(ctx, "state-1", p.nonce, challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("code_challenge") != challenge || q.Get("code_challenge_method") != pkceMethodS256 {
+		t.Fatalf("missing pkce params in %s", authURL)
+	}
+	if q.Get("client_id") != testClientID || q.Get("nonce") != p.nonce || q.Get("state") != "state-1" {
+		t.Fatalf("unexpected auth url %s", authURL)
+	}
+
+	token, err := c.Exchange(ctx, testCode, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := c.Claims(ctx, token, p.nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims["sub"] != testSubject {
+		t.Fatalf("sub = %v", claims["sub"])
+	}
+	// id token中的字段优先，userinfo只补充缺少的字段
+	if claims["email"] != "alice@example.com" {
+		t.Fatalf("email = %v", claims["email"])
+	}
+	if claims["name"] != "Alice" {
+		t.Fatalf("name = %v", claims["name"])
+	}
+}
+
+func TestExchangeWrongVerifier(t *testing.T) {
+	p := newStubProvider(t)
+	c := newTestClient(p)
+	_, challenge, err := GeneratePKCE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.challenge = challenge
+	_, err = c.Exchange(context.Background(), testCode, "wrong-verifier")
+	if err == nil || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Fatalf("expected invalid_grant, got %v", err)
+	}
+}
+
+func TestVerifyIDTokenNonceMismatch(t *testing.T) {
+	p := newStubProvider(t)
+	c := newTestClient(p)
+	verifier, challenge, err := GeneratePKCE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.challenge = challenge
+	p.nonce = "nonce-1"
+	token, err := c.Exchange(context.Background(), testCode, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.VerifyIDToken(context.Background(), token.IDToken, "nonce-2"); !errors.Is(err, ErrNonceMismatch) {
+		t.Fatalf("expected ErrNonceMismatch, got %v", err)
+	}
+}
+
+func TestVerifyIDTokenWrongAudience(t *testing.T) {
+	p := newStubProvider(t)
+	c := newTestClient(p)
+	verifier, challenge, err := GeneratePKCE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.challenge = challenge
+	p.audience = "another-client"
+	token, err := c.Exchange(context.Background(), testCode, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.VerifyIDToken(context.Background(), token.IDToken, ""); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestVerifyIDTokenWrongKey(t *testing.T) {
+	p := newStubProvider(t)
+	c := newTestClient(p)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss": p.server.URL,
+		"sub": testSubject,
+		"aud": testClientID,
+		"iat": now.Unix(),
+		"exp": now.Add(time.Minute).Unix(),
+	})
+	token.Header["kid"] = testKid
+	signed, err := token.SignedString(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.VerifyIDToken(context.Background(), signed, ""); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestVerifyIDTokenExtraAudience(t *testing.T) {
+	p := newStubProvider(t)
+	c := New(&Config{
+		Issuer:       p.server.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+		Audiences:    []string{"another-client"},
+		HTTPClient:   p.server.Client(),
+	})
+	verifier, challenge, err := GeneratePKCE()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.challenge = challenge
+	p.audience = "another-client"
+	token, err := c.Exchange(context.Background(), testCode, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.VerifyIDToken(context.Background(), token.IDToken, ""); err != nil {
+		t.Fatalf("expected extra audience accepted, got %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+// Package password
+// 密码强度策略：最小长度、字符类型以及常见/泄露密码检查
+package password
+
+import (
+	_ "embed"
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordList string
+
+var (
+	ErrTooShort      = errors.New("password too short")
+	ErrMissingUpper  = errors.New("password requires an uppercase letter")
+	ErrMissingLower  = errors.New("password requires a lowercase letter")
+	ErrMissingDigit  = errors.New("password requires a digit")
+	ErrMissingSymbol = errors.New("password requires a symbol")
+	ErrCommon        = errors.New("password is too common")
+
+	commonPasswords = loadCommonPasswords(commonPasswordList)
+)
+
+type Policy struct {
+	MinLength     int  // 最小长度，按字符计算
+	RequireUpper  bool // 必须包含大写字母
+	RequireLower  bool // 必须包含小写字母
+	RequireDigit  bool // 必须包含数字
+	RequireSymbol bool // 必须包含字母和数字以外的字符
+	RejectCommon  bool // 拒绝内置的常见或已泄露的密码，比较时忽略大小写
+}
+
+// Validate 按顺序检查，返回第一个不满足的规则
+func (p *Policy) Validate(password string) error {
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return ErrTooShort
+	}
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	if p.RequireUpper && !upper {
+		return ErrMissingUpper
+	}
+	if p.RequireLower && !lower {
+		return ErrMissingLower
+	}
+	if p.RequireDigit && !digit {
+		return ErrMissingDigit
+	}
+	if p.RequireSymbol && !symbol {
+		return ErrMissingSymbol
+	}
+	if p.RejectCommon && IsCommon(password) {
+		return ErrCommon
+	}
+	return nil
+}
+
+// IsCommon 密码是否在内置的常见密码列表中
+func IsCommon(password string) bool {
+	_, ok := commonPasswords[strings.ToLower(password)]
+	return ok
+}
+
+func loadCommonPasswords(list string) map[string]struct{} {
+	lines := strings.Split(list, "\n")
+	m := make(map[string]struct{}, len(lines))
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			m[strings.ToLower(line)] = struct{}{}
+		}
+	}
+	return m
+}
@@ -0,0 +1,57 @@
+package password
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	strict := &Policy{
+		MinLength:     8,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+		RejectCommon:  true,
+	}
+	tests := []struct {
+		name     string
+		policy   *Policy
+		password string
+		want     error
+	}{
+		{"empty policy", &Policy{}, "", nil},
+		{"too short", &Policy{MinLength: 8}, "Ab1!xyz", ErrTooShort},
+		{"length counts runes", &Policy{MinLength: 4}, "密码密码", nil},
+		{"multibyte too short", &Policy{MinLength: 5}, "密码密码", ErrTooShort},
+		{"missing upper", strict, "abcdef1!", ErrMissingUpper},
+		{"missing lower", strict, "ABCDEF1!", ErrMissingLower},
+		{"missing digit", strict, "Abcdefg!", ErrMissingDigit},
+		{"missing symbol", strict, "Abcdefg1", ErrMissingSymbol},
+		{"length checked first", strict, "abc", ErrTooShort},
+		{"common", &Policy{RejectCommon: true}, "password", ErrCommon},
+		{"common ignores case", &Policy{RejectCommon: true}, "PassWord123", ErrCommon},
+		{"common meets complexity", strict, "P@ssw0rd123", ErrCommon},
+		{"common allowed", &Policy{}, "123456", nil},
+		{"strong", strict, "Tr0ub4dor&3x", nil},
+		{"non-ascii symbol", &Policy{RequireSymbol: true}, "abc中文", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(tt.password); !errors.Is(err, tt.want) {
+				t.Fatalf("Validate(%q) = %v, want %v", tt.password, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommonList(t *testing.T) {
+	if len(commonPasswords) < 5000 {
+		t.Fatalf("common password list too small: %d", len(commonPasswords))
+	}
+	for _, p := range []string{"123456", "qwerty123", "woaini1314", "1q2w3e4r", "iloveyou"} {
+		if !IsCommon(p) {
+			t.Errorf("%q should be common", p)
+		}
+	}
+}
@@ -49,6 +49,11 @@ func New(c *configv1.RedisConfig) *Redis {
 	return r
 }
 
+// NewFromClient 使用已经创建的单节点client，e.g. 测试中连接miniredis
+func NewFromClient(client *redis.Client) *Redis {
+	return &Redis{Cmdable: client, client: client}
+}
+
 func (r *Redis) EvalShaWithReload(ctx context.Context, sha string, script string, keys []string, args ...interface{}) (any, error) {
 	res, err := r.EvalSha(ctx, sha, keys, args...).Result()
 	if err != nil && strings.HasPrefix(err.Error(), "NOSCRIPT") {
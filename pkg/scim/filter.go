@@ -0,0 +1,126 @@
+package scim
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Condition 过滤条件 attr eq "value"，attr统一转为小写
+type Condition struct {
+	Attr  string
+	Value string
+}
+
+// ParseFilter 解析过滤表达式，只支持eq和and，这也是IdP同步时实际使用的写法
+// e.g. userName eq "alice@example.com" and externalId eq "00u1"
+func ParseFilter(filter string) ([]Condition, error) {
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		return nil, nil
+	}
+	var conds []Condition
+	rest := filter
+	for {
+		cond, remain, err := parseCondition(rest)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+		remain = strings.TrimSpace(remain)
+		if remain == "" {
+			return conds, nil
+		}
+		keyword, next, ok := strings.Cut(remain, " ")
+		if !ok || !strings.EqualFold(keyword, "and") {
+			return nil, invalidFilter(filter)
+		}
+		rest = next
+	}
+}
+
+func parseCondition(s string) (Condition, string, error) {
+	s = strings.TrimSpace(s)
+	attr, rest, ok := strings.Cut(s, " ")
+	if !ok || attr == "" {
+		return Condition{}, "", invalidFilter(s)
+	}
+	rest = strings.TrimSpace(rest)
+	op, rest, ok := strings.Cut(rest, " ")
+	if !ok || !strings.EqualFold(op, "eq") {
+		return Condition{}, "", invalidFilter(s)
+	}
+	value, remain, err := parseQuoted(strings.TrimSpace(rest))
+	if err != nil {
+		return Condition{}, "", invalidFilter(s)
+	}
+	return Condition{Attr: strings.ToLower(attr), Value: value}, remain, nil
+}
+
+// parseQuoted 读取双引号包围的字符串，支持\"和\\转义
+func parseQuoted(s string) (value, remain string, err error) {
+	if !strings.HasPrefix(s, `"`) {
+		return "", "", invalidFilter(s)
+	}
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			if i+1 >= len(s) {
+				return "", "", invalidFilter(s)
+			}
+			i++
+			b.WriteByte(s[i])
+		case '"':
+			return b.String(), s[i+1:], nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", "", invalidFilter(s)
+}
+
+// ValuePath PATCH中带过滤的路径 attr[sub eq "value"].subAttr，没有过滤时Filter为nil
+type ValuePath struct {
+	Attr    string
+	Filter  *Condition
+	SubAttr string
+}
+
+// ParsePath 解析PATCH的path，attr和subAttr统一转为小写
+// e.g. active、name.givenName、members[value eq "123"]、emails[type eq "work"].value
+func ParsePath(path string) (*ValuePath, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, NewError(http.StatusBadRequest, ErrTypeInvalidPath, "empty path")
+	}
+	p := &ValuePath{}
+	attr, rest, hasFilter := strings.Cut(path, "[")
+	if hasFilter {
+		expr, remain, ok := strings.Cut(rest, "]")
+		if !ok {
+			return nil, NewError(http.StatusBadRequest, ErrTypeInvalidPath, path)
+		}
+		conds, err := ParseFilter(expr)
+		if err != nil || len(conds) != 1 {
+			return nil, NewError(http.StatusBadRequest, ErrTypeInvalidPath, path)
+		}
+		p.Filter = &conds[0]
+		if remain != "" {
+			if !strings.HasPrefix(remain, ".") {
+				return nil, NewError(http.StatusBadRequest, ErrTypeInvalidPath, path)
+			}
+			p.SubAttr = strings.ToLower(remain[1:])
+		}
+	} else if a, sub, ok := strings.Cut(attr, "."); ok {
+		attr, p.SubAttr = a, strings.ToLower(sub)
+	}
+	if attr == "" {
+		return nil, NewError(http.StatusBadRequest, ErrTypeInvalidPath, path)
+	}
+	p.Attr = strings.ToLower(attr)
+	return p, nil
+}
+
+func invalidFilter(filter string) error {
+	return NewError(http.StatusBadRequest, ErrTypeInvalidFilter, filter)
+}
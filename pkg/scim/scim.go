@@ -0,0 +1,211 @@
+// Package scim
+// SCIM 2.0 (RFC 7643/7644) 的资源定义、过滤表达式和PATCH操作的解析
+// 只包含协议部分，资源和存储之间的映射由调用方实现
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SchemaUser         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup        = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError        = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+	ContentType = "application/scim+json"
+
+	ResourceTypeUser  = "User"
+	ResourceTypeGroup = "Group"
+)
+
+const (
+	ErrTypeInvalidFilter = "invalidFilter"
+	ErrTypeInvalidPath   = "invalidPath"
+	ErrTypeInvalidValue  = "invalidValue"
+	ErrTypeInvalidSyntax = "invalidSyntax"
+	ErrTypeNoTarget      = "noTarget"
+	ErrTypeMutability    = "mutability"
+	ErrTypeUniqueness    = "uniqueness"
+)
+
+type Meta struct {
+	ResourceType string     `json:"resourceType,omitempty"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Location     string     `json:"location,omitempty"`
+}
+
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+}
+
+// MultiValued emails、phoneNumbers、groups、members等多值属性的元素
+type MultiValued struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+type User struct {
+	Schemas     []string      `json:"schemas"`
+	ID          string        `json:"id,omitempty"`
+	ExternalID  string        `json:"externalId,omitempty"`
+	UserName    string        `json:"userName"`
+	Name        *Name         `json:"name,omitempty"`
+	DisplayName string        `json:"displayName,omitempty"`
+	NickName    string        `json:"nickName,omitempty"`
+	Active      *bool         `json:"active,omitempty"`
+	Emails      []MultiValued `json:"emails,omitempty"`
+	Groups      []MultiValued `json:"groups,omitempty"`
+	Meta        *Meta         `json:"meta,omitempty"`
+}
+
+// PrimaryEmail 返回primary的邮箱，没有时返回第一个
+func (u *User) PrimaryEmail() string {
+	for _, e := range u.Emails {
+		if e.Primary {
+			return e.Value
+		}
+	}
+	if len(u.Emails) > 0 {
+		return u.Emails[0].Value
+	}
+	return ""
+}
+
+type Group struct {
+	Schemas     []string      `json:"schemas"`
+	ID          string        `json:"id,omitempty"`
+	ExternalID  string        `json:"externalId,omitempty"`
+	DisplayName string        `json:"displayName"`
+	Members     []MultiValued `json:"members,omitempty"`
+	Meta        *Meta         `json:"meta,omitempty"`
+}
+
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int64    `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+func NewListResponse(total int64, startIndex int, resources []any) *ListResponse {
+	if resources == nil {
+		resources = []any{}
+	}
+	return &ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: total,
+		StartIndex:   startIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+type PatchOp struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// PatchOperation op不区分大小写，部分IdP会发送Add、Replace
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+const (
+	OpAdd     = "add"
+	OpReplace = "replace"
+	OpRemove  = "remove"
+)
+
+// Validate 检查schema和op，返回小写的op
+func (p *PatchOp) Validate() error {
+	if len(p.Schemas) != 1 || p.Schemas[0] != SchemaPatchOp || len(p.Operations) == 0 {
+		return NewError(http.StatusBadRequest, ErrTypeInvalidSyntax, "invalid PatchOp")
+	}
+	for i := range p.Operations {
+		op := strings.ToLower(p.Operations[i].Op)
+		switch op {
+		case OpAdd, OpReplace:
+			if len(p.Operations[i].Value) == 0 {
+				return NewError(http.StatusBadRequest, ErrTypeInvalidValue, "missing value")
+			}
+		case OpRemove:
+			if p.Operations[i].Path == "" {
+				return NewError(http.StatusBadRequest, ErrTypeNoTarget, "remove requires path")
+			}
+		default:
+			return NewError(http.StatusBadRequest, ErrTypeInvalidSyntax, fmt.Sprintf("unsupported op %q", p.Operations[i].Op))
+		}
+		p.Operations[i].Op = op
+	}
+	return nil
+}
+
+// Bool 解析布尔值，兼容部分IdP把active发送为"True"、"False"字符串
+func Bool(raw json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(raw, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return false, NewError(http.StatusBadRequest, ErrTypeInvalidValue, "invalid boolean")
+	}
+	b, err := strconv.ParseBool(strings.ToLower(s))
+	if err != nil {
+		return false, NewError(http.StatusBadRequest, ErrTypeInvalidValue, "invalid boolean")
+	}
+	return b, nil
+}
+
+// String 解析字符串值
+func String(raw json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return "", NewError(http.StatusBadRequest, ErrTypeInvalidValue, "invalid string")
+	}
+	return s, nil
+}
+
+// Error SCIM错误响应，status在json中是字符串
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+
+	code int
+}
+
+func NewError(code int, scimType, detail string) *Error {
+	return &Error{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(code),
+		ScimType: scimType,
+		Detail:   detail,
+		code:     code,
+	}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("scim: %s %s %s", e.Status, e.ScimType, e.Detail)
+}
+
+func (e *Error) Code() int {
+	return e.code
+}
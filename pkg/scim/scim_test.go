@@ -0,0 +1,75 @@
+package scim
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseFilter(t *testing.T) {
+	conds, err := ParseFilter(`userName eq "alice@example.com" AND externalId eq "a\"b"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Condition{
+		{Attr: "username", Value: "alice@example.com"},
+		{Attr: "externalid", Value: `a"b`},
+	}
+	if !reflect.DeepEqual(conds, want) {
+		t.Fatalf("got %+v, want %+v", conds, want)
+	}
+	for _, filter := range []string{
+		`userName sw "a"`,
+		`userName eq alice`,
+		`userName eq "a" or displayName eq "b"`,
+		`userName eq "a`,
+	} {
+		if _, err := ParseFilter(filter); err == nil {
+			t.Fatalf("expected error for %q", filter)
+		}
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	cases := map[string]ValuePath{
+		"active":                       {Attr: "active"},
+		"name.givenName":               {Attr: "name", SubAttr: "givenname"},
+		`members[value eq "42"]`:       {Attr: "members", Filter: &Condition{Attr: "value", Value: "42"}},
+		`emails[type eq "work"].value`: {Attr: "emails", Filter: &Condition{Attr: "type", Value: "work"}, SubAttr: "value"},
+	}
+	for path, want := range cases {
+		got, err := ParsePath(path)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		if !reflect.DeepEqual(*got, want) {
+			t.Fatalf("%s: got %+v, want %+v", path, *got, want)
+		}
+	}
+	if _, err := ParsePath(`members[value eq "42"`); err == nil {
+		t.Fatal("expected error for unclosed filter")
+	}
+}
+
+func TestPatchOpValidate(t *testing.T) {
+	p := &PatchOp{
+		Schemas: []string{SchemaPatchOp},
+		Operations: []PatchOperation{
+			{Op: "Replace", Path: "active", Value: json.RawMessage(`"False"`)},
+		},
+	}
+	if err := p.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if p.Operations[0].Op != OpReplace {
+		t.Fatalf("op not normalized: %s", p.Operations[0].Op)
+	}
+	active, err := Bool(p.Operations[0].Value)
+	if err != nil || active {
+		t.Fatalf("got %v, %v", active, err)
+	}
+	p.Operations = []PatchOperation{{Op: "remove"}}
+	if err := p.Validate(); err == nil {
+		t.Fatal("expected error for remove without path")
+	}
+}
@@ -1,24 +1,35 @@
 package sms
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/byteflowing/base/pkg/utils"
 	"github.com/byteflowing/go-common/jsonx"
+	enumv1 "github.com/byteflowing/proto/gen/go/enums/v1"
 	msgv1 "github.com/byteflowing/proto/gen/go/msg/v1"
+	"github.com/volcengine/volc-sdk-golang/base"
 	"github.com/volcengine/volc-sdk-golang/service/sms"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // 文档：https://www.volcengine.com/docs/6361/67380
 // go sdk：https://www.volcengine.com/docs/6361/1109261
 
+const (
+	dateFormat = "20060102"
+)
+
 type Sms struct {
 	accessKeyId     string
 	accessKeySecret string
 	cli             *sms.SMS
 }
 
+// NewSms 每个账号使用独立的实例，sms.DefaultInstance是全局共享的，多个账号会互相覆盖密钥
 func NewSms(opts *msgv1.SmsProvider) *Sms {
-	cli := sms.DefaultInstance
+	cli := sms.NewInstance()
 	cli.Client.SetAccessKey(opts.AccessKey)
 	cli.Client.SetSecretKey(opts.SecretKey)
 	return &Sms{
@@ -40,25 +51,98 @@ func (s *Sms) SendSms(req *msgv1.SendSmsReq) (resp *msgv1.SendSmsResp, err error
 		TemplateParam: params,
 		PhoneNumbers:  req.PhoneNumber.Number,
 	})
-	err = s.parseErr(res, err)
+	var metadata *base.ResponseMetadata
+	if res != nil {
+		metadata = &res.ResponseMetadata
+	}
+	err = s.parseErr(metadata, err)
 	return
 }
 
-func (s *Sms) parseErr(resp *sms.SmsResponse, err error) error {
-	if err != nil {
-		if resp != nil {
-			return fmt.Errorf(
-				"requestID: %s, action: %s, version: %s, service: %s, grpc_geo: %s, messageID: %v, err: %v",
-				resp.ResponseMetadata.RequestId,
-				resp.ResponseMetadata.Action,
-				resp.ResponseMetadata.Version,
-				resp.ResponseMetadata.Service,
-				resp.ResponseMetadata.Region,
-				resp.Result.MessageID,
-				err,
-			)
-		}
+// QuerySendDetail 查询单个手机号在某一天的发送明细，SmsAccount使用消息组ID
+func (s *Sms) QuerySendDetail(req *msgv1.QuerySmsSendDetailReq) (resp *msgv1.QuerySmsSendDetailResp, err error) {
+	if req.PhoneNumber == nil || req.SendDate == nil {
+		return nil, errors.New("phone number or send date is nil")
+	}
+	res, _, err := s.cli.GetSmsSendDetails(&sms.GetSmsSendDetailsRequest{
+		SmsAccount:  req.Account,
+		PhoneNumber: req.PhoneNumber.Number,
+		SendDate:    req.SendDate.AsTime().Format(dateFormat),
+		PageIndex:   int64(req.Page),
+		PageSize:    int64(req.Size),
+	})
+	var metadata *base.ResponseMetadata
+	if res != nil {
+		metadata = &res.ResponseMetadata
+	}
+	if err := s.parseErr(metadata, err); err != nil {
+		return nil, err
+	}
+	if res.Result == nil {
+		return nil, errors.New("response result is nil")
+	}
+	details := make([]*msgv1.SmsSendDetail, 0, len(res.Result.List))
+	for _, d := range res.Result.List {
+		details = append(details, &msgv1.SmsSendDetail{
+			PhoneNumber:  d.PhoneNumber,
+			Content:      d.Content,
+			Status:       s.volcSendStatusToStatus(d.Status),
+			TemplateCode: d.TemplateId,
+			ErrCode:      d.ErrorCode,
+			SendDate:     unixToTimestamp(d.SendTime),
+			ReceiveDate:  unixToTimestamp(d.ReceiptTime),
+		})
+	}
+	total := int64(res.Result.Total)
+	resp = &msgv1.QuerySmsSendDetailResp{
+		Page:       req.Page,
+		Size:       req.Size,
+		Total:      total,
+		TotalPages: utils.CalcTotalPage(total, req.Size),
+		Vendor:     req.Vendor,
+		Account:    req.Account,
+		Details:    details,
+	}
+	return resp, nil
+}
+
+// parseErr 请求失败时sdk可能返回err，也可能只在ResponseMetadata.Error中返回错误
+func (s *Sms) parseErr(metadata *base.ResponseMetadata, err error) error {
+	if metadata == nil {
 		return err
 	}
-	return nil
+	if err == nil && metadata.Error == nil {
+		return nil
+	}
+	if err == nil {
+		err = fmt.Errorf("code: %s, message: %s", metadata.Error.Code, metadata.Error.Message)
+	}
+	return fmt.Errorf(
+		"requestID: %s, action: %s, version: %s, service: %s, region: %s, err: %w",
+		metadata.RequestId,
+		metadata.Action,
+		metadata.Version,
+		metadata.Service,
+		metadata.Region,
+		err,
+	)
+}
+
+func (s *Sms) volcSendStatusToStatus(st sms.SendLogStatus) enumv1.SmsSendStatus {
+	switch st {
+	case sms.SendNoReceipt:
+		return enumv1.SmsSendStatus_SMS_SEND_STATUS_WAIT_RESPONSE
+	case sms.SendFail:
+		return enumv1.SmsSendStatus_SMS_SEND_STATUS_FAILED
+	case sms.SendAndReceipt, sms.Verify, sms.Click:
+		return enumv1.SmsSendStatus_SMS_SEND_STATUS_SUCCESS
+	}
+	return enumv1.SmsSendStatus_SMS_SEND_STATUS_UNSPECIFIED
+}
+
+func unixToTimestamp(sec int64) *timestamppb.Timestamp {
+	if sec <= 0 {
+		return nil
+	}
+	return timestamppb.New(time.Unix(sec, 0))
 }
@@ -39,6 +39,12 @@ func New(clientID, clientSecret string) *Account {
 	}
 }
 
+// Close 关闭空闲的http连接，正在进行的请求不受影响
+func (a *Account) Close() error {
+	a.httpClient.CloseIdleConnections()
+	return nil
+}
+
 // SignIn 华为账号一键登录
 // 文档：https://developer.huawei.com/consumer/cn/doc/harmonyos-references/account-api-get-user-info-quicklogin-by-code
 func (a *Account) SignIn(ctx context.Context, req *huaweiv1.HuaweiSignInReq) (resp *huaweiv1.HuaweiSignInResp, err error) {
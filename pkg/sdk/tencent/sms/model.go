@@ -0,0 +1,102 @@
+package sms
+
+// 接口文档：https://cloud.tencent.com/document/product/382/55981
+
+type SendSmsRequest struct {
+	PhoneNumberSet   []string // E.164格式，e.g. +8613711112222
+	SmsSdkAppId      string
+	SignName         string // 国内短信必填
+	TemplateId       string
+	TemplateParamSet []string `json:",omitempty"` // 按顺序替换模板中的{1}{2}...
+	ExtendCode       string   `json:",omitempty"`
+	SessionContext   string   `json:",omitempty"` // 原样返回，可以用于关联回执
+	SenderId         string   `json:",omitempty"` // 国际短信使用
+}
+
+type SendSmsResponse struct {
+	SendStatusSet []*SendStatus
+	RequestId     string
+}
+
+type SendStatus struct {
+	SerialNo       string
+	PhoneNumber    string
+	Fee            int64
+	SessionContext string
+	Code           string // 成功时为Ok
+	Message        string
+	IsoCode        string
+}
+
+type PullSmsSendStatusRequest struct {
+	Limit       int64 // 最大100
+	SmsSdkAppId string
+}
+
+type PullSmsSendStatusByPhoneNumberRequest struct {
+	BeginTime   int64 // unix时间戳，单位秒
+	EndTime     int64 `json:",omitempty"`
+	Offset      int64
+	Limit       int64 // 最大100
+	PhoneNumber string
+	SmsSdkAppId string
+}
+
+type PullSmsSendStatusResponse struct {
+	PullSmsSendStatusSet []*PullSmsSendStatus
+	RequestId            string
+}
+
+type PullSmsSendStatus struct {
+	UserReceiveTime  string // e.g. 2019-10-08 17:18:37
+	CountryCode      string
+	SubscriberNumber string
+	PhoneNumber      string
+	SerialNo         string
+	ReportStatus     string // SUCCESS 或 FAIL
+	Description      string
+	SessionContext   string
+}
+
+// StatisticsRequest 时间格式为yyyymmddhh，e.g. 2019071100
+// Limit和Offset目前固定为0
+type StatisticsRequest struct {
+	BeginTime   string
+	EndTime     string
+	SmsSdkAppId string
+	Limit       int64
+	Offset      int64
+}
+
+type SendStatusStatisticsResponse struct {
+	SendStatusStatistics *SendStatusStatistics
+	RequestId            string
+}
+
+type SendStatusStatistics struct {
+	FeeCount            int64
+	RequestCount        int64
+	RequestSuccessCount int64
+}
+
+type CallbackStatusStatisticsResponse struct {
+	CallbackStatusStatistics *CallbackStatusStatistics
+	RequestId                string
+}
+
+type CallbackStatusStatistics struct {
+	CallbackCount        int64
+	RequestSuccessCount  int64
+	CallbackFailCount    int64
+	CallbackSuccessCount int64
+	InternalErrorCount   int64
+	InvalidNumberCount   int64
+	ShutdownErrorCount   int64
+	BlackListCount       int64
+	FrequencyLimitCount  int64
+}
+
+type apiError struct {
+	Code    string
+	Message string
+}
@@ -0,0 +1,58 @@
+package sms
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 签名文档：https://cloud.tencent.com/document/api/382/52072
+
+const (
+	signAlgorithm = "TC3-HMAC-SHA256"
+	signedHeaders = "content-type;host;x-tc-action"
+	contentType   = "application/json; charset=utf-8"
+)
+
+// sign 生成TC3-HMAC-SHA256签名的Authorization头
+func sign(secretID, secretKey, service, host, action string, payload []byte, ts time.Time) string {
+	date := ts.UTC().Format("2006-01-02")
+	canonicalHeaders := fmt.Sprintf("content-type:%s\nhost:%s\nx-tc-action:%s\n", contentType, host, strings.ToLower(action))
+	canonicalRequest := strings.Join([]string{
+		"POST",
+		"/",
+		"",
+		canonicalHeaders,
+		signedHeaders,
+		sha256Hex(payload),
+	}, "\n")
+	scope := fmt.Sprintf("%s/%s/tc3_request", date, service)
+	stringToSign := strings.Join([]string{
+		signAlgorithm,
+		fmt.Sprintf("%d", ts.Unix()),
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+	secretDate := hmacSha256([]byte("TC3"+secretKey), date)
+	secretService := hmacSha256(secretDate, service)
+	secretSigning := hmacSha256(secretService, "tc3_request")
+	signature := hex.EncodeToString(hmacSha256(secretSigning, stringToSign))
+	return fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signAlgorithm, secretID, scope, signedHeaders, signature,
+	)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
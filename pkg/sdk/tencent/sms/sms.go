@@ -0,0 +1,176 @@
+// Package sms
+// 腾讯云短信API 3.0，文档：https://cloud.tencent.com/document/product/382/52077
+// 直接调用HTTP接口，不依赖腾讯云的go sdk
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/byteflowing/base/pkg/httpx"
+)
+
+const (
+	service         = "sms"
+	version         = "2021-01-11"
+	defaultEndpoint = "https://sms.tencentcloudapi.com"
+	defaultRegion   = "ap-guangzhou"
+
+	StatusOk            = "Ok"      // 发送成功
+	ReportStatusSuccess = "SUCCESS" // 用户接收成功
+	ReportStatusFail    = "FAIL"    // 用户接收失败
+)
+
+type Config struct {
+	SecretID  string
+	SecretKey string
+	Token     string // 临时密钥的token，使用永久密钥时为空
+	Region    string // 为空时使用ap-guangzhou
+	Endpoint  string // 为空时使用https://sms.tencentcloudapi.com
+}
+
+// Error 接口返回的错误，文档：https://cloud.tencent.com/document/api/382/52075
+type Error struct {
+	Code      string
+	Message   string
+	RequestID string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("[requestID:%s, code:%s] errMsg:%s", e.RequestID, e.Code, e.Message)
+}
+
+type Sms struct {
+	cfg        *Config
+	host       string
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+func New(cfg *Config) (*Sms, error) {
+	if cfg.SecretID == "" || cfg.SecretKey == "" {
+		return nil, errors.New("secret id or secret key is empty")
+	}
+	c := *cfg
+	if c.Region == "" {
+		c.Region = defaultRegion
+	}
+	if c.Endpoint == "" {
+		c.Endpoint = defaultEndpoint
+	}
+	u, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &Sms{
+		cfg:        &c,
+		host:       u.Host,
+		httpClient: httpx.NewClient(httpx.GetDefaultConfig()),
+		now:        time.Now,
+	}, nil
+}
+
+// SendSms 发送短信，每个号码的发送结果在SendStatusSet中，Code不为Ok时表示该号码发送失败
+func (s *Sms) SendSms(ctx context.Context, req *SendSmsRequest) (*SendSmsResponse, error) {
+	resp := &SendSmsResponse{}
+	if err := s.do(ctx, "SendSms", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// PullSmsSendStatus 拉取短信回执，已拉取过的回执不会再次返回
+func (s *Sms) PullSmsSendStatus(ctx context.Context, req *PullSmsSendStatusRequest) (*PullSmsSendStatusResponse, error) {
+	resp := &PullSmsSendStatusResponse{}
+	if err := s.do(ctx, "PullSmsSendStatus", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// PullSmsSendStatusByPhoneNumber 拉取单个号码在一段时间内的短信回执，可以重复拉取
+func (s *Sms) PullSmsSendStatusByPhoneNumber(ctx context.Context, req *PullSmsSendStatusByPhoneNumberRequest) (*PullSmsSendStatusResponse, error) {
+	resp := &PullSmsSendStatusResponse{}
+	if err := s.do(ctx, "PullSmsSendStatusByPhoneNumber", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// SendStatusStatistics 发送数据统计
+func (s *Sms) SendStatusStatistics(ctx context.Context, req *StatisticsRequest) (*SendStatusStatisticsResponse, error) {
+	resp := &SendStatusStatisticsResponse{}
+	if err := s.do(ctx, "SendStatusStatistics", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CallbackStatusStatistics 回执数据统计
+func (s *Sms) CallbackStatusStatistics(ctx context.Context, req *StatisticsRequest) (*CallbackStatusStatisticsResponse, error) {
+	resp := &CallbackStatusStatisticsResponse{}
+	if err := s.do(ctx, "CallbackStatusStatistics", req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// do 签名并发送请求，接口返回的数据在Response字段中，出错时Response.Error不为空
+func (s *Sms) do(ctx context.Context, action string, req, resp any) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.Endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	ts := s.now()
+	request.Host = s.host
+	request.Header.Set("Content-Type", contentType)
+	request.Header.Set("Authorization", sign(s.cfg.SecretID, s.cfg.SecretKey, service, s.host, action, payload, ts))
+	request.Header.Set("X-TC-Action", action)
+	request.Header.Set("X-TC-Timestamp", strconv.FormatInt(ts.Unix(), 10))
+	request.Header.Set("X-TC-Version", version)
+	request.Header.Set("X-TC-Region", s.cfg.Region)
+	if s.cfg.Token != "" {
+		request.Header.Set("X-TC-Token", s.cfg.Token)
+	}
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %d, body: %s", response.StatusCode, body)
+	}
+	var envelope struct {
+		Response json.RawMessage
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return err
+	}
+	var result struct {
+		Error     *apiError
+		RequestId string
+	}
+	if err := json.Unmarshal(envelope.Response, &result); err != nil {
+		return err
+	}
+	if result.Error != nil {
+		return &Error{Code: result.Error.Code, Message: result.Error.Message, RequestID: result.RequestId}
+	}
+	return json.Unmarshal(envelope.Response, resp)
+}
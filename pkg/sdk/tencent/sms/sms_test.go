@@ -0,0 +1,244 @@
+package sms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testSecretID  = "AKIDtest"
+	testSecretKey = "test-secret-key"
+	testAppID     = "1400000000"
+)
+
+// newTestServer 模拟腾讯云API，校验签名后按X-TC-Action返回handlers中的响应
+func newTestServer(t *testing.T, handlers map[string]func(body map[string]any) any) *httptest.Server {
+	t.Helper()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read body: %v", err)
+			return
+		}
+		if err := verifySignature(r, body); err != nil {
+			writeJSON(w, map[string]any{"Response": map[string]any{
+				"Error":     map[string]any{"Code": "AuthFailure.SignatureFailure", "Message": err.Error()},
+				"RequestId": "req-sign",
+			}})
+			return
+		}
+		if r.Header.Get("X-TC-Version") != version || r.Header.Get("X-TC-Region") != defaultRegion {
+			t.Errorf("unexpected version or region: %s %s", r.Header.Get("X-TC-Version"), r.Header.Get("X-TC-Region"))
+		}
+		handler, ok := handlers[r.Header.Get("X-TC-Action")]
+		if !ok {
+			t.Errorf("unexpected action: %s", r.Header.Get("X-TC-Action"))
+			return
+		}
+		var params map[string]any
+		if err := json.Unmarshal(body, &params); err != nil {
+			t.Errorf("unmarshal body: %v", err)
+			return
+		}
+		writeJSON(w, map[string]any{"Response": handler(params)})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// verifySignature 按文档的步骤独立计算签名
+func verifySignature(r *http.Request, body []byte) error {
+	ts, err := strconv.ParseInt(r.Header.Get("X-TC-Timestamp"), 10, 64)
+	if err != nil {
+		return err
+	}
+	date := time.Unix(ts, 0).UTC().Format("2006-01-02")
+	hashedPayload := sha256.Sum256(body)
+	canonicalRequest := "POST\n/\n\n" +
+		"content-type:" + r.Header.Get("Content-Type") + "\n" +
+		"host:" + r.Host + "\n" +
+		"x-tc-action:" + strings.ToLower(r.Header.Get("X-TC-Action")) + "\n\n" +
+		"content-type;host;x-tc-action\n" +
+		hex.EncodeToString(hashedPayload[:])
+	hashedRequest := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "TC3-HMAC-SHA256\n" + strconv.FormatInt(ts, 10) + "\n" + date + "/sms/tc3_request\n" + hex.EncodeToString(hashedRequest[:])
+	mac := func(key []byte, data string) []byte {
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(data))
+		return h.Sum(nil)
+	}
+	key := mac(mac(mac([]byte("TC3"+testSecretKey), date), "sms"), "tc3_request")
+	expected := "TC3-HMAC-SHA256 Credential=" + testSecretID + "/" + date + "/sms/tc3_request, " +
+		"SignedHeaders=content-type;host;x-tc-action, Signature=" + hex.EncodeToString(mac(key, stringToSign))
+	if r.Header.Get("Authorization") != expected {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newTestSms(t *testing.T, endpoint, secretKey string) *Sms {
+	t.Helper()
+	s, err := New(&Config{SecretID: testSecretID, SecretKey: secretKey, Endpoint: endpoint})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSendSms(t *testing.T) {
+	server := newTestServer(t, map[string]func(body map[string]any) any{
+		"SendSms": func(body map[string]any) any {
+			if body["SmsSdkAppId"] != testAppID || body["TemplateId"] != "1001" {
+				t.Errorf("unexpected body: %v", body)
+			}
+			params, _ := body["TemplateParamSet"].([]any)
+			if len(params) != 2 || params[0] != "1234" || params[1] != "5" {
+				t.Errorf("unexpected template params: %v", body["TemplateParamSet"])
+			}
+			return map[string]any{
+				"SendStatusSet": []map[string]any{{
+					"SerialNo":    "2019:538884*********",
+					"PhoneNumber": "+8613711112222",
+					"Fee":         1,
+					"Code":        "Ok",
+					"Message":     "send success",
+					"IsoCode":     "CN",
+				}},
+				"RequestId": "req-send",
+			}
+		},
+	})
+	s := newTestSms(t, server.URL, testSecretKey)
+	resp, err := s.SendSms(context.Background(), &SendSmsRequest{
+		PhoneNumberSet:   []string{"+8613711112222"},
+		SmsSdkAppId:      testAppID,
+		SignName:         "test",
+		TemplateId:       "1001",
+		TemplateParamSet: []string{"1234", "5"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.RequestId != "req-send" || len(resp.SendStatusSet) != 1 || resp.SendStatusSet[0].Code != StatusOk {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestSignatureFailure(t *testing.T) {
+	server := newTestServer(t, nil)
+	s := newTestSms(t, server.URL, "wrong-secret-key")
+	_, err := s.SendSms(context.Background(), &SendSmsRequest{PhoneNumberSet: []string{"+8613711112222"}})
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	if apiErr.Code != "AuthFailure.SignatureFailure" || apiErr.RequestID != "req-sign" {
+		t.Fatalf("unexpected error: %+v", apiErr)
+	}
+}
+
+func TestPullSmsSendStatus(t *testing.T) {
+	status := map[string]any{
+		"PullSmsSendStatusSet": []map[string]any{{
+			"UserReceiveTime":  "2019-10-08 17:18:37",
+			"CountryCode":      "86",
+			"SubscriberNumber": "13711112222",
+			"PhoneNumber":      "+8613711112222",
+			"SerialNo":         "2019:538884*********",
+			"ReportStatus":     "SUCCESS",
+			"Description":      "DELIVRD",
+		}},
+		"RequestId": "req-pull",
+	}
+	server := newTestServer(t, map[string]func(body map[string]any) any{
+		"PullSmsSendStatus": func(body map[string]any) any {
+			if body["Limit"] != float64(10) {
+				t.Errorf("unexpected limit: %v", body["Limit"])
+			}
+			return status
+		},
+		"PullSmsSendStatusByPhoneNumber": func(body map[string]any) any {
+			if body["PhoneNumber"] != "+8613711112222" || body["BeginTime"] != float64(1570521600) {
+				t.Errorf("unexpected body: %v", body)
+			}
+			return status
+		},
+	})
+	s := newTestSms(t, server.URL, testSecretKey)
+	resp, err := s.PullSmsSendStatus(context.Background(), &PullSmsSendStatusRequest{Limit: 10, SmsSdkAppId: testAppID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.PullSmsSendStatusSet) != 1 || resp.PullSmsSendStatusSet[0].ReportStatus != ReportStatusSuccess {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	resp, err = s.PullSmsSendStatusByPhoneNumber(context.Background(), &PullSmsSendStatusByPhoneNumberRequest{
+		BeginTime:   1570521600,
+		Limit:       10,
+		PhoneNumber: "+8613711112222",
+		SmsSdkAppId: testAppID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.PullSmsSendStatusSet) != 1 || resp.PullSmsSendStatusSet[0].SubscriberNumber != "13711112222" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestStatistics(t *testing.T) {
+	server := newTestServer(t, map[string]func(body map[string]any) any{
+		"SendStatusStatistics": func(body map[string]any) any {
+			if body["BeginTime"] != "2019071100" || body["EndTime"] != "2019071123" {
+				t.Errorf("unexpected body: %v", body)
+			}
+			return map[string]any{
+				"SendStatusStatistics": map[string]any{"FeeCount": 3, "RequestCount": 2, "RequestSuccessCount": 2},
+				"RequestId":            "req-send-stat",
+			}
+		},
+		"CallbackStatusStatistics": func(body map[string]any) any {
+			return map[string]any{
+				"CallbackStatusStatistics": map[string]any{
+					"CallbackCount":        2,
+					"RequestSuccessCount":  2,
+					"CallbackFailCount":    1,
+					"CallbackSuccessCount": 1,
+				},
+				"RequestId": "req-callback-stat",
+			}
+		},
+	})
+	s := newTestSms(t, server.URL, testSecretKey)
+	req := &StatisticsRequest{BeginTime: "2019071100", EndTime: "2019071123", SmsSdkAppId: testAppID}
+	sendStat, err := s.SendStatusStatistics(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sendStat.SendStatusStatistics.FeeCount != 3 || sendStat.SendStatusStatistics.RequestSuccessCount != 2 {
+		t.Fatalf("unexpected response: %+v", sendStat.SendStatusStatistics)
+	}
+	callbackStat, err := s.CallbackStatusStatistics(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if callbackStat.CallbackStatusStatistics.CallbackFailCount != 1 {
+		t.Fatalf("unexpected response: %+v", callbackStat.CallbackStatusStatistics)
+	}
+}
@@ -0,0 +1,201 @@
+// Package totp
+// RFC 6238 基于时间的一次性密码实现
+// 文档：https://datatracker.ietf.org/doc/html/rfc6238
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPeriod     = 30
+	defaultDigits     = 6
+	defaultSecretSize = 20
+
+	recoveryCodeCharset = "abcdefghjkmnpqrstuvwxyz23456789"
+	recoveryCodeLength  = 10
+)
+
+var (
+	ErrInvalidSecret = errors.New("invalid totp secret")
+	ErrInvalidCode   = errors.New("invalid totp code")
+
+	b32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+type Config struct {
+	Period uint32 // 时间步长，单位秒，默认30
+	Digits uint32 // 验证码位数，默认6
+	Skew   uint32 // 允许前后偏移的时间步数，用于容忍客户端时钟误差
+}
+
+type TOTP struct {
+	period uint64
+	digits uint32
+	skew   uint32
+}
+
+func New(cfg *Config) *TOTP {
+	t := &TOTP{
+		period: defaultPeriod,
+		digits: defaultDigits,
+	}
+	if cfg == nil {
+		return t
+	}
+	if cfg.Period > 0 {
+		t.period = uint64(cfg.Period)
+	}
+	if cfg.Digits >= 6 && cfg.Digits <= 8 {
+		t.digits = cfg.Digits
+	}
+	t.skew = cfg.Skew
+	return t
+}
+
+// GenerateSecret 生成base32编码的随机密钥
+func GenerateSecret() (string, error) {
+	buf := make([]byte, defaultSecretSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return b32.EncodeToString(buf), nil
+}
+
+// GenerateRecoveryCodes 生成count个一次性恢复码，格式为 xxxxx-xxxxx
+// 恢复码只在生成时返回给用户一次，服务端仅保存其哈希值
+func GenerateRecoveryCodes(count int) ([]string, error) {
+	codes := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		chars, err := randomChars(recoveryCodeCharset, recoveryCodeLength)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, string(chars[:recoveryCodeLength/2])+"-"+string(chars[recoveryCodeLength/2:]))
+	}
+	return codes, nil
+}
+
+// randomChars 从charset中均匀地随机选取n个字符
+// 字符集长度不能整除256，直接取模会使前面的字符概率偏高，超出整数倍范围的字节丢弃重取
+func randomChars(charset string, n int) ([]byte, error) {
+	size := len(charset)
+	limit := 256 - 256%size
+	chars := make([]byte, 0, n)
+	buf := make([]byte, n)
+	for len(chars) < n {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		for _, b := range buf {
+			if int(b) >= limit {
+				continue
+			}
+			chars = append(chars, charset[int(b)%size])
+			if len(chars) == n {
+				break
+			}
+		}
+	}
+	return chars, nil
+}
+
+// NormalizeRecoveryCode 统一恢复码格式，忽略用户输入时的大小写、空格与分隔符
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, " ", "")
+	code = strings.ReplaceAll(code, "-", "")
+	if len(code) != recoveryCodeLength {
+		return code
+	}
+	return code[:recoveryCodeLength/2] + "-" + code[recoveryCodeLength/2:]
+}
+
+// URI 生成otpauth格式的uri，客户端可以将其渲染为二维码供身份验证器扫描
+// 格式：otpauth://totp/{issuer}:{account}?secret=xxx&issuer=xxx&algorithm=SHA1&digits=6&period=30
+func (t *TOTP) URI(issuer, account, secret string) string {
+	label := url.PathEscape(account)
+	if issuer != "" {
+		label = url.PathEscape(issuer) + ":" + label
+	}
+	params := url.Values{}
+	params.Set("secret", secret)
+	if issuer != "" {
+		params.Set("issuer", issuer)
+	}
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", strconv.FormatUint(uint64(t.digits), 10))
+	params.Set("period", strconv.FormatUint(t.period, 10))
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// Generate 生成时间点at对应的验证码
+func (t *TOTP) Generate(secret string, at time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return t.generate(key, t.step(at)), nil
+}
+
+// Validate 校验验证码，成功时返回验证码对应的时间步
+// 调用方应该记录最后一次使用的时间步，拒绝小于等于该值的时间步来防止重放
+func (t *TOTP) Validate(secret, code string, at time.Time) (step uint64, err error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return 0, err
+	}
+	code = strings.TrimSpace(code)
+	if len(code) != int(t.digits) {
+		return 0, ErrInvalidCode
+	}
+	current := t.step(at)
+	for i := -int64(t.skew); i <= int64(t.skew); i++ {
+		s := uint64(int64(current) + i)
+		if subtle.ConstantTimeCompare([]byte(t.generate(key, s)), []byte(code)) == 1 {
+			return s, nil
+		}
+	}
+	return 0, ErrInvalidCode
+}
+
+func (t *TOTP) step(at time.Time) uint64 {
+	return uint64(at.Unix()) / t.period
+}
+
+// generate RFC 4226 HOTP算法
+func (t *TOTP) generate(key []byte, counter uint64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := uint32(0); i < t.digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", t.digits, value%mod)
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.ReplaceAll(secret, " ", ""))
+	secret = strings.TrimRight(secret, "=")
+	key, err := b32.DecodeString(secret)
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return key, nil
+}
@@ -0,0 +1,168 @@
+package totp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret RFC 6238附录B中SHA1使用的密钥"12345678901234567890"的base32编码
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateRFC6238(t *testing.T) {
+	totp := New(&Config{Digits: 8})
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	for _, tt := range tests {
+		code, err := totp.Generate(rfcSecret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != tt.code {
+			t.Errorf("Generate(%d) = %s, want %s", tt.unix, code, tt.code)
+		}
+		step, err := totp.Validate(rfcSecret, tt.code, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Errorf("Validate(%d) failed: %v", tt.unix, err)
+		}
+		if want := uint64(tt.unix / 30); step != want {
+			t.Errorf("Validate(%d) step = %d, want %d", tt.unix, step, want)
+		}
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	period := 30 * time.Second
+	tests := []struct {
+		name   string
+		skew   uint32
+		offset int
+		valid  bool
+	}{
+		{"current step without skew", 0, 0, true},
+		{"previous step without skew", 0, -1, false},
+		{"previous step", 1, -1, true},
+		{"next step", 1, 1, true},
+		{"outside window", 1, -2, false},
+		{"outside window ahead", 1, 2, false},
+		{"wider window", 2, -2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			totp := New(&Config{Skew: tt.skew})
+			at := now.Add(time.Duration(tt.offset) * period)
+			code, err := totp.Generate(rfcSecret, at)
+			if err != nil {
+				t.Fatal(err)
+			}
+			step, err := totp.Validate(rfcSecret, code, now)
+			if !tt.valid {
+				if !errors.Is(err, ErrInvalidCode) {
+					t.Fatalf("expected ErrInvalidCode, got step %d, err %v", step, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := uint64(int64(totp.step(now)) + int64(tt.offset)); step != want {
+				t.Fatalf("step = %d, want %d", step, want)
+			}
+		})
+	}
+}
+
+// TestValidateReplay 调用方记录last_step，只接受大于last_step的时间步
+func TestValidateReplay(t *testing.T) {
+	totp := New(&Config{Skew: 1})
+	now := time.Unix(1234567890, 0)
+	var lastStep uint64
+	accept := func(code string, at time.Time) bool {
+		step, err := totp.Validate(rfcSecret, code, at)
+		if err != nil || step <= lastStep {
+			return false
+		}
+		lastStep = step
+		return true
+	}
+	code, err := totp.Generate(rfcSecret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !accept(code, now) {
+		t.Fatal("first use should be accepted")
+	}
+	if accept(code, now) {
+		t.Fatal("replayed code should be rejected")
+	}
+	// 下一个时间步时上一个验证码仍在偏移窗口内，但时间步没有增大
+	if accept(code, now.Add(30*time.Second)) {
+		t.Fatal("replayed code within skew should be rejected")
+	}
+	previous, err := totp.Generate(rfcSecret, now.Add(-30*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if accept(previous, now) {
+		t.Fatal("older code should be rejected after a newer one was used")
+	}
+	next, err := totp.Generate(rfcSecret, now.Add(30*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !accept(next, now.Add(30*time.Second)) {
+		t.Fatal("code of the next step should be accepted")
+	}
+}
+
+func TestValidateInvalid(t *testing.T) {
+	totp := New(nil)
+	now := time.Unix(1234567890, 0)
+	if _, err := totp.Validate("not base32!", "123456", now); !errors.Is(err, ErrInvalidSecret) {
+		t.Fatalf("expected ErrInvalidSecret, got %v", err)
+	}
+	if _, err := totp.Validate(rfcSecret, "12345", now); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("expected ErrInvalidCode, got %v", err)
+	}
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool, len(codes))
+	counts := make(map[rune]int)
+	for _, code := range codes {
+		if len(code) != recoveryCodeLength+1 || code[recoveryCodeLength/2] != '-' {
+			t.Fatalf("unexpected format: %s", code)
+		}
+		if seen[code] {
+			t.Fatalf("duplicated code: %s", code)
+		}
+		seen[code] = true
+		if NormalizeRecoveryCode(strings.ToUpper(strings.ReplaceAll(code, "-", " "))) != code {
+			t.Fatalf("normalize mismatch: %s", code)
+		}
+		for _, r := range strings.ReplaceAll(code, "-", "") {
+			if !strings.ContainsRune(recoveryCodeCharset, r) {
+				t.Fatalf("unexpected char %q in %s", r, code)
+			}
+			counts[r]++
+		}
+	}
+	if len(counts) != len(recoveryCodeCharset) {
+		t.Fatalf("expected all %d chars to appear, got %d", len(recoveryCodeCharset), len(counts))
+	}
+}
@@ -0,0 +1,47 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+)
+
+// AesGcm AES-256-GCM加密，密文格式为nonce+ciphertext
+type AesGcm struct {
+	aead cipher.AEAD
+}
+
+// NewAesGcm 使用key的sha256作为AES-256的密钥，key可以是任意长度的字符串
+func NewAesGcm(key string) (*AesGcm, error) {
+	if key == "" {
+		return nil, errors.New("empty key")
+	}
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AesGcm{aead: aead}, nil
+}
+
+func (a *AesGcm) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, a.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return a.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (a *AesGcm) Decrypt(data []byte) ([]byte, error) {
+	size := a.aead.NonceSize()
+	if len(data) < size {
+		return nil, errors.New("ciphertext too short")
+	}
+	return a.aead.Open(nil, data[:size], data[size:], nil)
+}
@@ -2,6 +2,7 @@ package crypto
 
 import (
 	"crypto/md5"
+	"crypto/sha256"
 	"fmt"
 
 	"github.com/spaolacci/murmur3"
@@ -19,6 +20,17 @@ func Md5Hex(data []byte) string {
 	return fmt.Sprintf("%x", Md5(data))
 }
 
+// Sha256 returns the sha256 bytes of data.
+func Sha256(data []byte) []byte {
+	digest := sha256.Sum256(data)
+	return digest[:]
+}
+
+// Sha256Hex returns the sha256 hex string of data.
+func Sha256Hex(data []byte) string {
+	return fmt.Sprintf("%x", Sha256(data))
+}
+
 // Hash returns the hash value of data.
 func Hash(data []byte) uint64 {
 	return murmur3.Sum64(data)
@@ -108,7 +108,7 @@ func NewAsynqServer(config *configv1.AsynqServerConfig, rdb *redis.Redis) *asynq
 
 func NewAsynqClient(rdb *redis.Redis) *asynqx.Client {
 	asynqClientOnce.Do(func() {
-		asynqx.NewClientFromRDB(rdb)
+		asynqClient = asynqx.NewClientFromRDB(rdb)
 	})
 	return asynqClient
 }